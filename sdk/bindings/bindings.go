@@ -0,0 +1,8 @@
+// Package bindings contains the typed contract bindings of the ERC-8004 registries and
+// Multicall3, generated with abigen from the ABIs in the abi directory.
+package bindings
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --v2 --abi abi/identity-registry.json --pkg bindings --type IdentityRegistry --out identity-registry.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --v2 --abi abi/reputation-registry.json --pkg bindings --type ReputationRegistry --out reputation-registry.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --v2 --abi abi/validation-registry.json --pkg bindings --type ValidationRegistry --out validation-registry.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --v2 --abi abi/multicall3.json --pkg bindings --type Multicall3 --out multicall3.go
@@ -0,0 +1,235 @@
+// Package caip parses, formats and validates chain agnostic identifiers: CAIP-2 chain
+// IDs (eip155:84532), CAIP-10 accounts (eip155:84532:0xabc...) and the ERC-8004 global
+// agent IDs, which are the CAIP-10 account of the identity registry followed by the token
+// ID of the agent (eip155:84532:0x8004...:123).
+//
+// The SDK also identifies agents by the short form chainID:tokenID (84532:123), which is
+// accepted wherever an agent ID is parsed (the registry is unknown in the short form).
+package caip
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NAMESPACE_EIP155 is the CAIP-2 namespace of EVM chains.
+const NAMESPACE_EIP155 = "eip155"
+
+var (
+	namespacePattern = regexp.MustCompile(`^[-a-z0-9]{3,8}$`)
+	referencePattern = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,32}$`)
+	addressPattern   = regexp.MustCompile(`^[-.%a-zA-Z0-9]{1,128}$`)
+	eip155Pattern    = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+)
+
+// ErrInvalidIdentifier is returned (wrapped) for every malformed identifier.
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// ParseChainID parses a CAIP-2 chain ID (namespace:reference).
+func ParseChainID(value string) (ChainID, error) {
+	namespace, reference, ok := strings.Cut(value, ":")
+	if !ok {
+		return ChainID{}, invalid("chain ID %q: expected namespace:reference", value)
+	}
+
+	chainID := ChainID{Namespace: namespace, Reference: reference}
+	if err := chainID.Validate(); err != nil {
+		return ChainID{}, err
+	}
+	return chainID, nil
+}
+
+// EIP155ChainID returns the CAIP-2 chain ID of an EVM chain.
+func EIP155ChainID(chainID int64) ChainID {
+	return ChainID{Namespace: NAMESPACE_EIP155, Reference: strconv.FormatInt(chainID, 10)}
+}
+
+// Validate checks the namespace and reference of the chain ID (EVM chain references
+// must be decimal chain IDs).
+func (c ChainID) Validate() error {
+	if !namespacePattern.MatchString(c.Namespace) {
+		return invalid("chain namespace %q", c.Namespace)
+	}
+	if !referencePattern.MatchString(c.Reference) {
+		return invalid("chain reference %q", c.Reference)
+	}
+	if c.Namespace == NAMESPACE_EIP155 {
+		if _, err := c.EVMChainID(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EVMChainID returns the chain ID of an EVM (eip155) chain.
+func (c ChainID) EVMChainID() (int64, error) {
+	if c.Namespace != NAMESPACE_EIP155 {
+		return 0, invalid("chain %s is not an EVM chain", c)
+	}
+	if !eip155Pattern.MatchString(c.Reference) {
+		return 0, invalid("EVM chain reference %q", c.Reference)
+	}
+	chainID, err := strconv.ParseInt(c.Reference, 10, 64)
+	if err != nil {
+		return 0, invalid("EVM chain reference %q", c.Reference)
+	}
+	return chainID, nil
+}
+
+// String formats the chain ID (namespace:reference).
+func (c ChainID) String() string {
+	return c.Namespace + ":" + c.Reference
+}
+
+// ParseAccountID parses a CAIP-10 account ID (namespace:reference:address).
+func ParseAccountID(value string) (AccountID, error) {
+	idx := strings.LastIndex(value, ":")
+	if idx < 0 || strings.Count(value, ":") != 2 {
+		return AccountID{}, invalid("account ID %q: expected namespace:reference:address", value)
+	}
+
+	chainID, err := ParseChainID(value[:idx])
+	if err != nil {
+		return AccountID{}, err
+	}
+
+	accountID := AccountID{Chain: chainID, Address: value[idx+1:]}
+	if err := accountID.Validate(); err != nil {
+		return AccountID{}, err
+	}
+	return accountID, nil
+}
+
+// EIP155AccountID returns the CAIP-10 account ID of an address on an EVM chain.
+func EIP155AccountID(chainID int64, address string) AccountID {
+	return AccountID{Chain: EIP155ChainID(chainID), Address: address}
+}
+
+// Validate checks the chain and address of the account (EVM addresses must be hex).
+func (a AccountID) Validate() error {
+	if err := a.Chain.Validate(); err != nil {
+		return err
+	}
+	if !addressPattern.MatchString(a.Address) {
+		return invalid("account address %q", a.Address)
+	}
+	if a.Chain.Namespace == NAMESPACE_EIP155 && !common.IsHexAddress(a.Address) {
+		return invalid("EVM address %q", a.Address)
+	}
+	return nil
+}
+
+// String formats the account ID (namespace:reference:address).
+func (a AccountID) String() string {
+	return a.Chain.String() + ":" + a.Address
+}
+
+// ParseAgentID parses an ERC-8004 global agent ID (namespace:reference:registry:tokenID)
+// or an SDK agent ID (chainID:tokenID, EVM chains only).
+func ParseAgentID(value string) (AgentID, error) {
+	parts := strings.Split(value, ":")
+	switch len(parts) {
+	case 2:
+		chainID := ChainID{Namespace: NAMESPACE_EIP155, Reference: parts[0]}
+		if err := chainID.Validate(); err != nil {
+			return AgentID{}, fmt.Errorf("agent ID %q: %w", value, err)
+		}
+		tokenID, err := parseTokenID(parts[1])
+		if err != nil {
+			return AgentID{}, fmt.Errorf("agent ID %q: %w", value, err)
+		}
+		return AgentID{Chain: chainID, TokenID: tokenID}, nil
+	case 4:
+		registry, err := ParseAccountID(strings.Join(parts[:3], ":"))
+		if err != nil {
+			return AgentID{}, fmt.Errorf("agent ID %q: %w", value, err)
+		}
+		tokenID, err := parseTokenID(parts[3])
+		if err != nil {
+			return AgentID{}, fmt.Errorf("agent ID %q: %w", value, err)
+		}
+		return AgentID{Chain: registry.Chain, Registry: registry.Address, TokenID: tokenID}, nil
+	default:
+		return AgentID{}, invalid("agent ID %q: expected namespace:reference:registry:tokenID or chainID:tokenID", value)
+	}
+}
+
+// FormatAgentID formats the ERC-8004 global agent ID of an agent of an EVM chain.
+func FormatAgentID(chainID int64, registry string, tokenID *big.Int) string {
+	return AgentID{Chain: EIP155ChainID(chainID), Registry: registry, TokenID: tokenID}.String()
+}
+
+// Validate checks the chain, registry (when known) and token ID of the agent ID.
+func (a AgentID) Validate() error {
+	if a.Registry != "" {
+		if err := a.RegistryAccount().Validate(); err != nil {
+			return err
+		}
+	} else if err := a.Chain.Validate(); err != nil {
+		return err
+	}
+	if a.TokenID == nil || a.TokenID.Sign() < 0 || a.TokenID.BitLen() > 256 {
+		return invalid("token ID %v", a.TokenID)
+	}
+	return nil
+}
+
+// RegistryAccount returns the CAIP-10 account of the identity registry of the agent
+// (the agentRegistry of the ERC-8004 registrations).
+func (a AgentID) RegistryAccount() AccountID {
+	return AccountID{Chain: a.Chain, Address: a.Registry}
+}
+
+// Short formats the SDK agent ID (chainID:tokenID).
+func (a AgentID) Short() string {
+	return a.Chain.Reference + ":" + a.TokenID.String()
+}
+
+// String formats the global agent ID, or the SDK agent ID when the registry is unknown.
+func (a AgentID) String() string {
+	if a.Registry == "" {
+		return a.Short()
+	}
+	return a.RegistryAccount().String() + ":" + a.TokenID.String()
+}
+
+// parseTokenID parses a decimal ERC-721 token ID (uint256).
+func parseTokenID(value string) (*big.Int, error) {
+	tokenID, ok := new(big.Int).SetString(value, 10)
+	if !ok || tokenID.Sign() < 0 || value[0] == '+' {
+		return nil, invalid("token ID %q", value)
+	}
+	if tokenID.BitLen() > 256 {
+		return nil, invalid("token ID %q: out of uint256 range", value)
+	}
+	return tokenID, nil
+}
+
+// invalid returns an invalid identifier error.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidIdentifier, fmt.Sprintf(format, args...))
+}
+
+// ...
+
+type ChainID struct {
+	Namespace string // e.g. eip155
+	Reference string // e.g. 84532
+}
+
+type AccountID struct {
+	Chain   ChainID
+	Address string
+}
+
+type AgentID struct {
+	Chain    ChainID
+	Registry string   // identity registry address ("" for SDK agent IDs)
+	TokenID  *big.Int // ERC-721 token ID (uint256)
+}
@@ -0,0 +1,219 @@
+package caip
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+const registry = "0x8004A818BFB912233c491871b3d84c89A494BD9e"
+
+func TestParseChainID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ChainID
+		valid bool
+	}{
+		{value: "eip155:1", want: ChainID{Namespace: "eip155", Reference: "1"}, valid: true},
+		{value: "eip155:84532", want: ChainID{Namespace: "eip155", Reference: "84532"}, valid: true},
+		{value: "eip155:0", want: ChainID{Namespace: "eip155", Reference: "0"}, valid: true},
+		{value: "cosmos:cosmoshub-4", want: ChainID{Namespace: "cosmos", Reference: "cosmoshub-4"}, valid: true},
+		{value: "84532"},
+		{value: "eip155:"},
+		{value: ":1"},
+		{value: "EIP155:1"},
+		{value: "ab:1"},
+		{value: "eip155:0x1"},
+		{value: "eip155:01"},
+		{value: "eip155:-1"},
+		{value: "eip155:99999999999999999999"},
+		{value: "eip155:1:2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseChainID(tt.value)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidIdentifier) {
+					t.Fatalf("ParseChainID(%q) error = %v, want ErrInvalidIdentifier", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseChainID(%q) error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseChainID(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestEVMChainID(t *testing.T) {
+	chainID, err := EIP155ChainID(84532).EVMChainID()
+	if err != nil || chainID != 84532 {
+		t.Errorf("EVMChainID() = %d, %v, want 84532", chainID, err)
+	}
+
+	if _, err := (ChainID{Namespace: "cosmos", Reference: "cosmoshub-4"}).EVMChainID(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("EVMChainID() of a non-EVM chain error = %v, want ErrInvalidIdentifier", err)
+	}
+}
+
+func TestParseAccountID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  AccountID
+		valid bool
+	}{
+		{
+			value: "eip155:84532:" + registry,
+			want:  AccountID{Chain: ChainID{Namespace: "eip155", Reference: "84532"}, Address: registry},
+			valid: true,
+		},
+		{
+			value: "cosmos:cosmoshub-4:cosmos1abc",
+			want:  AccountID{Chain: ChainID{Namespace: "cosmos", Reference: "cosmoshub-4"}, Address: "cosmos1abc"},
+			valid: true,
+		},
+		{value: "eip155:84532"},
+		{value: "eip155:84532:"},
+		{value: "eip155:84532:0x1234"},
+		{value: "eip155:84532:" + registry + ":1"},
+		{value: "eip155:x:" + registry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseAccountID(tt.value)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidIdentifier) {
+					t.Fatalf("ParseAccountID(%q) error = %v, want ErrInvalidIdentifier", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAccountID(%q) error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAccountID(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestParseAgentID(t *testing.T) {
+	maxTokenID := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	tests := []struct {
+		name     string
+		value    string
+		registry string
+		tokenID  *big.Int
+		short    string
+		valid    bool
+	}{
+		{
+			name:     "global",
+			value:    "eip155:84532:" + registry + ":123",
+			registry: registry,
+			tokenID:  big.NewInt(123),
+			short:    "84532:123",
+			valid:    true,
+		},
+		{
+			name:    "short",
+			value:   "84532:0",
+			tokenID: big.NewInt(0),
+			short:   "84532:0",
+			valid:   true,
+		},
+		{
+			name:     "max token ID",
+			value:    "eip155:1:" + registry + ":" + maxTokenID.String(),
+			registry: registry,
+			tokenID:  maxTokenID,
+			short:    "1:" + maxTokenID.String(),
+			valid:    true,
+		},
+		{name: "token ID only", value: "123"},
+		{name: "empty token ID", value: "84532:"},
+		{name: "negative token ID", value: "84532:-1"},
+		{name: "signed token ID", value: "84532:+1"},
+		{name: "hex token ID", value: "84532:0x1"},
+		{name: "token ID out of range", value: "1:" + new(big.Int).Add(maxTokenID, big.NewInt(1)).String()},
+		{name: "invalid short chain", value: "base:1"},
+		{name: "invalid registry", value: "eip155:84532:0x1234:1"},
+		{name: "missing registry", value: "eip155:84532:1"},
+		{name: "too many parts", value: "eip155:84532:" + registry + ":1:2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAgentID(tt.value)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidIdentifier) {
+					t.Fatalf("ParseAgentID(%q) error = %v, want ErrInvalidIdentifier", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAgentID(%q) error: %v", tt.value, err)
+			}
+			if got.Registry != tt.registry || got.TokenID.Cmp(tt.tokenID) != 0 {
+				t.Errorf("ParseAgentID(%q) = %+v, want registry %q and token ID %v", tt.value, got, tt.registry, tt.tokenID)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+			if got.Short() != tt.short {
+				t.Errorf("Short() = %q, want %q", got.Short(), tt.short)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFormatAgentID(t *testing.T) {
+	got := FormatAgentID(84532, registry, big.NewInt(7))
+	want := "eip155:84532:" + registry + ":7"
+	if got != want {
+		t.Errorf("FormatAgentID() = %q, want %q", got, want)
+	}
+
+	parsed, err := ParseAgentID(got)
+	if err != nil {
+		t.Fatalf("ParseAgentID(%q) error: %v", got, err)
+	}
+	if parsed.RegistryAccount().String() != "eip155:84532:"+registry {
+		t.Errorf("RegistryAccount() = %q", parsed.RegistryAccount())
+	}
+}
+
+func TestAgentIDValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		agentID AgentID
+	}{
+		{name: "nil token ID", agentID: AgentID{Chain: EIP155ChainID(1)}},
+		{name: "negative token ID", agentID: AgentID{Chain: EIP155ChainID(1), TokenID: big.NewInt(-1)}},
+		{name: "invalid chain", agentID: AgentID{Chain: ChainID{Namespace: "eip155", Reference: "x"}, TokenID: big.NewInt(1)}},
+		{name: "invalid registry", agentID: AgentID{Chain: EIP155ChainID(1), Registry: "0x1", TokenID: big.NewInt(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.agentID.Validate(); !errors.Is(err, ErrInvalidIdentifier) {
+				t.Errorf("Validate() error = %v, want ErrInvalidIdentifier", err)
+			}
+		})
+	}
+}
@@ -1,28 +1,48 @@
 package core
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
 	"math/big"
+	"slices"
+	"time"
 
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/ryanchristo/agent0-go/sdk/bindings"
+	"github.com/ryanchristo/agent0-go/sdk/caip"
 	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
 )
 
 // Agent is an agent instance for managing individual agents.
 type Agent struct {
+	sdk                  *SDK
 	registrationFile     types.RegistrationFile
 	endpointCrawler      EndpointCrawler
 	dirtyMetadata        map[string]bool
 	lastRegisteredWallet types.Address
 	lastRegisteredENS    string
+	flowNonce            string // identifies the journal flows of the agent before registration
 }
 
 // newAgent creates a new agent instance.
-func newAgent(registrationFile types.RegistrationFile) *Agent {
-	return &Agent{
-		registrationFile: registrationFile,
-		endpointCrawler:  NewEndpointCrawler(5000),
+func newAgent(sdk *SDK, registrationFile types.RegistrationFile) *Agent {
+	agent := &Agent{
+		sdk:                  sdk,
+		registrationFile:     registrationFile,
+		endpointCrawler:      NewEndpointCrawler(5000),
+		dirtyMetadata:        map[string]bool{},
+		lastRegisteredWallet: registrationFile.WalletAddress,
 	}
+	agent.lastRegisteredENS = agent.ENSEndpoint()
+	return agent
 }
 
 // AgentID returns the agent ID.
@@ -30,6 +50,14 @@ func (a *Agent) AgentID() types.AgentID {
 	return a.registrationFile.AgentID
 }
 
+// TokenID returns the ERC-721 token ID of the agent (nil if not registered).
+func (a *Agent) TokenID() *big.Int {
+	if a.registrationFile.AgentID == "" {
+		return nil
+	}
+	return utils.ParseAgentID(a.registrationFile.AgentID).TokenID
+}
+
 // AgentURI returns the agent URI.
 func (a *Agent) AgentURI() types.URI {
 	return a.registrationFile.AgentURI
@@ -157,26 +185,55 @@ func (a *Agent) SetENS(name, version string) *Agent {
 }
 
 // getOrCreateOASFEndpoint gets or creates the OASF endpoint.
-func (a *Agent) getOrCreateOASFEndpoint() types.Endpoint {
+func (a *Agent) getOrCreateOASFEndpoint() *types.Endpoint {
+	for idx := range a.registrationFile.Endpoints {
+		if endpoint := &a.registrationFile.Endpoints[idx]; endpoint.Type == types.ENDPOINT_TYPE_OASF {
+			// Endpoints loaded from registration files may have no metadata
+			if endpoint.Meta == nil {
+				endpoint.Meta = map[string]any{}
+			}
+			return endpoint
+		}
+	}
 
-	// TODO: implementation
+	a.registrationFile.Endpoints = append(a.registrationFile.Endpoints, types.Endpoint{
+		Type:  types.ENDPOINT_TYPE_OASF,
+		Value: "https://github.com/agntcy/oasf/",
+		Meta: map[string]any{
+			"version": "v0.8.0",
+			"skills":  []string{},
+			"domains": []string{},
+		},
+	})
 
-	return types.Endpoint{}
+	return &a.registrationFile.Endpoints[len(a.registrationFile.Endpoints)-1]
 }
 
 // AddSkills adds a skill to the OASF endpoint.
 func (a *Agent) AddSkill(slug string, validateOASF bool) *Agent {
 	// default validateOASF = false
 
-	// TODO: implementation
+	if validateOASF && !ValidateSkill(slug) {
+		log.Fatalf("Invalid OASF skill slug: %s", slug)
+	}
+
+	endpoint := a.getOrCreateOASFEndpoint()
+	skills := metaStrings(endpoint.Meta, "skills")
+	if !slices.Contains(skills, slug) {
+		endpoint.Meta["skills"] = append(skills, slug)
+	}
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 
 	return a
 }
 
 // RemoveSkill removes a skill from the OASF endpoint.
 func (a *Agent) RemoveSkill(slug string) *Agent {
-
-	// TODO: implementation
+	endpoint := a.getOrCreateOASFEndpoint()
+	endpoint.Meta["skills"] = slices.DeleteFunc(metaStrings(endpoint.Meta, "skills"), func(skill string) bool {
+		return skill == slug
+	})
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 
 	return a
 }
@@ -185,27 +242,72 @@ func (a *Agent) RemoveSkill(slug string) *Agent {
 func (a *Agent) AddDomain(slug string, validateOASF bool) *Agent {
 	// default validateOASF = false
 
-	// TODO: implementation
+	if validateOASF && !ValidateDomain(slug) {
+		log.Fatalf("Invalid OASF domain slug: %s", slug)
+	}
+
+	endpoint := a.getOrCreateOASFEndpoint()
+	domains := metaStrings(endpoint.Meta, "domains")
+	if !slices.Contains(domains, slug) {
+		endpoint.Meta["domains"] = append(domains, slug)
+	}
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 
 	return a
 }
 
 // RemoveDomain removes a domain from the OASF endpoint.
 func (a *Agent) RemoveDomain(slug string) *Agent {
-
-	// TODO: implementation
+	endpoint := a.getOrCreateOASFEndpoint()
+	endpoint.Meta["domains"] = slices.DeleteFunc(metaStrings(endpoint.Meta, "domains"), func(domain string) bool {
+		return domain == slug
+	})
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 
 	return a
 }
 
-// SetAgentWallet sets the agent wallet address and the associated chain ID.
-func (a *Agent) SetAgentWallet(address types.Address, chainID int64) *Agent {
+// OASFSkills returns the agent OASF skills.
+func (a *Agent) OASFSkills() []string {
+	for _, endpoint := range a.registrationFile.Endpoints {
+		if endpoint.Type == types.ENDPOINT_TYPE_OASF {
+			return metaStrings(endpoint.Meta, "skills")
+		}
+	}
+	return []string{}
+}
 
-	// TODO: implementation
+// OASFDomains returns the agent OASF domains.
+func (a *Agent) OASFDomains() []string {
+	for _, endpoint := range a.registrationFile.Endpoints {
+		if endpoint.Type == types.ENDPOINT_TYPE_OASF {
+			return metaStrings(endpoint.Meta, "domains")
+		}
+	}
+	return []string{}
+}
+
+// SetAgentWallet sets the agent wallet address and the associated chain ID.
+func (a *Agent) SetAgentWallet(address types.Address, chainID types.ChainID) *Agent {
+	if err := address.Validate(); err != nil {
+		log.Fatalf("Invalid wallet address: %v", err)
+	}
 
+	a.registrationFile.WalletAddress = types.Address(address.Checksum())
+	a.registrationFile.WalletChainID = chainID
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 	return a
 }
 
+// SetAgentWalletFromHD sets the agent wallet to the HD wallet agent wallet with the given index.
+func (a *Agent) SetAgentWalletFromHD(wallet *HDWallet, index uint32, chainID types.ChainID) *Agent {
+	agentWallet, err := wallet.AgentWallet(index)
+	if err != nil {
+		log.Fatalf("Failed to derive agent wallet: %v", err)
+	}
+	return a.SetAgentWallet(types.AddressFromCommon(agentWallet.Address()), chainID)
+}
+
 // SetActive sets the active status of the agent.
 func (a *Agent) SetActive(active bool) *Agent {
 
@@ -235,8 +337,14 @@ func (a *Agent) SetTrust(reputation, cryptoEconomics, teeAttestation bool) *Agen
 
 // SetMetadata sets the metadata of the agent.
 func (a *Agent) SetMetadata(kv map[string]any) *Agent {
-
-	// TODO: implementation
+	if a.registrationFile.Metadata == nil {
+		a.registrationFile.Metadata = map[string]any{}
+	}
+	for key, value := range kv {
+		a.registrationFile.Metadata[key] = value
+		a.dirtyMetadata[key] = true
+	}
+	a.registrationFile.UpdatedAt = time.Now().Unix()
 
 	return a
 }
@@ -251,8 +359,11 @@ func (a *Agent) GetMetadata(kv map[string]any) map[string]any {
 
 // DelMetadata deletes metadata from the agent.
 func (a *Agent) DelMetadata(key string) *Agent {
-
-	// TODO: implementation
+	if _, ok := a.registrationFile.Metadata[key]; ok {
+		delete(a.registrationFile.Metadata, key)
+		a.dirtyMetadata[key] = true
+		a.registrationFile.UpdatedAt = time.Now().Unix()
+	}
 
 	return a
 }
@@ -270,79 +381,549 @@ func (a *Agent) UpdateInfo(name, description string, image types.URI) *Agent {
 	return a
 }
 
-// RegisterIPFS registers the agent on chain using the IPFS workflow.
+// FlowNonce returns the random nonce identifying the journal flows of the agent before
+// it is registered (generated on first use). Store it with the agent, and restore it with
+// SetFlowNonce after a crash to resume an incomplete registration.
+func (a *Agent) FlowNonce() string {
+	if a.flowNonce == "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			log.Fatalf("Failed to generate flow nonce: %v", err)
+		}
+		a.flowNonce = hex.EncodeToString(nonce)
+	}
+	return a.flowNonce
+}
+
+// SetFlowNonce sets the flow nonce of the agent (see FlowNonce).
+func (a *Agent) SetFlowNonce(nonce string) *Agent {
+	a.flowNonce = nonce
+	return a
+}
+
+// RegisterIPFS registers the agent on chain using the IPFS workflow: the agent is
+// registered (or its metadata updated), the registration file is uploaded to IPFS and
+// the agent URI is set. With a transaction journal configured, an incomplete registration
+// of the same agent (see FlowNonce) is resumed instead of registering a duplicate agent.
 func (a *Agent) RegisterIPFS() types.RegistrationFile {
+	a.requireSigner()
+	if a.sdk.ipfsClient == nil {
+		log.Fatal("IPFS client is required for IPFS registration")
+	}
 
-	// TODO: implementation
+	flow := a.sdk.beginFlow(JOURNAL_FLOW_REGISTER_IPFS, a.flowKey(), a.registrationFile)
+
+	if a.registrationFile.AgentID == "" {
+		a.registerWithoutURI(flow)
+	} else {
+		a.requireUpdatePreflight()
+		a.updateMetadataOnChain(flow)
+	}
+
+	// Upload the registration file (including the agent ID)
+	cid := ""
+	if step, ok := flow.Step("uploadIPFS"); ok && step.Status == JOURNAL_STEP_STATUS_CONFIRMED {
+		cid = step.Result
+	} else {
+		// Uploads are content-addressed, so an unrecorded upload is safe to repeat
+		if err := flow.Intend("uploadIPFS"); err != nil {
+			log.Printf("warning: %v", err)
+		}
+		cid = a.sdk.ipfsClient.AddRegistrationFile(a.registrationFile, a.sdk.ChainID(), a.sdk.registries["IDENTITY"])
+		flow.Confirm("uploadIPFS", cid)
+	}
+
+	a.setAgentURI(flow, "ipfs://"+cid)
+	flow.Complete()
 
 	return a.registrationFile
 }
 
-// RegisterHTTP registers the agent on chain using the HTTP workflow.
+// RegisterHTTP registers the agent on chain using the HTTP workflow (the registration
+// file is hosted at the agent URI). Incomplete registrations are resumed like RegisterIPFS.
 func (a *Agent) RegisterHTTP(agentURI types.URI) types.RegistrationFile {
+	a.requireSigner()
 
-	// TODO: implementation
+	flow := a.sdk.beginFlow(JOURNAL_FLOW_REGISTER_HTTP, a.flowKey(), a.registrationFile)
+	flow.SetData("agentUri", agentURI)
+
+	if a.registrationFile.AgentID == "" {
+		a.registerWithURI(flow, agentURI)
+	} else {
+		a.requireUpdatePreflight()
+		a.updateMetadataOnChain(flow)
+		a.setAgentURI(flow, agentURI)
+	}
+	flow.Complete()
 
 	return a.registrationFile
 }
 
-// SetAgentURI sets the agent URI (used for updating the agent).
-func (a *Agent) SetAgentURI(agentURI types.URI) {
+// RegisterOnChains registers the agent on several chains (one SDK instance per chain,
+// e.g. from MultiChainSDK.Chain) with a single registration file: the agent is registered
+// on every chain, then the registration file listing all registrations is uploaded to IPFS
+// once and set as the agent URI on every chain. The agent itself takes the registration
+// on its own chain (or the first chain). Incomplete registrations are resumed by calling
+// RegisterOnChains again with the same chains. Chains listed in the registrations of the
+// registration file (set by RegisterOnChains) are updated instead of registered again.
+func (a *Agent) RegisterOnChains(chains []*SDK) MultiChainRegistration {
+	a.requireSigner()
+	if a.sdk.ipfsClient == nil {
+		log.Fatal("IPFS client is required for multi-chain registration")
+	}
+	if len(chains) == 0 {
+		log.Fatal("No chain to register the agent on")
+	}
 
-	// TODO: implementation
+	// Register (or update) the agent on every chain
+	agents := make([]*Agent, len(chains))
+	flows := make([]*JournalFlow, len(chains))
+	registrations := make([]AgentRegistration, len(chains))
+	for idx, sdk := range chains {
+		if sdk.IsReadOnly() {
+			log.Fatalf("Cannot register agent on chain %d: SDK is in read-only mode", sdk.ChainID())
+		}
+		if slices.ContainsFunc(chains[:idx], func(other *SDK) bool { return other.ChainID() == sdk.ChainID() }) {
+			log.Fatalf("Chain %d is listed more than once", sdk.ChainID())
+		}
+
+		agent := a
+		if sdk.ChainID() != a.sdk.ChainID() {
+			registrationFile := a.registrationFile
+			registrationFile.AgentID = a.registrationOn(sdk)
+			registrationFile.AgentURI = ""
+			agent = newAgent(sdk, registrationFile)
+			agent.flowNonce = a.FlowNonce() // the chain is part of the flow key
+			agent.lastRegisteredWallet = a.lastRegisteredWallet
+			agent.lastRegisteredENS = a.lastRegisteredENS
+			maps.Copy(agent.dirtyMetadata, a.dirtyMetadata)
+		}
+
+		flows[idx] = sdk.beginFlow(JOURNAL_FLOW_REGISTER_MULTI_CHAIN, agent.flowKey(), agent.registrationFile)
+		if agent.registrationFile.AgentID == "" {
+			agent.registerWithoutURI(flows[idx])
+		} else {
+			agent.updateMetadataOnChain(flows[idx])
+		}
+
+		agents[idx] = agent
+		registrations[idx] = AgentRegistration{
+			AgentID:          agent.registrationFile.AgentID,
+			IdentityRegistry: sdk.registries["IDENTITY"],
+		}
+	}
+
+	// Upload the registration file once (the CID only depends on the registrations)
+	owner := agents[0]
+	if idx := slices.Index(agents, a); idx >= 0 {
+		owner = agents[idx]
+	}
+	cid := ""
+	for _, flow := range flows {
+		if step, ok := flow.Step("uploadIPFS"); ok && step.Status == JOURNAL_STEP_STATUS_CONFIRMED {
+			cid = step.Result
+		}
+	}
+	if cid == "" {
+		// Uploads are content-addressed, so an unrecorded upload is safe to repeat
+		for _, flow := range flows {
+			if err := flow.Intend("uploadIPFS"); err != nil {
+				log.Printf("warning: %v", err)
+			}
+		}
+		cid = a.sdk.ipfsClient.AddMultiChainRegistrationFile(owner.registrationFile, owner.sdk.ChainID(), registrations)
+		for _, flow := range flows {
+			flow.Confirm("uploadIPFS", cid)
+		}
+	}
+
+	// Set the agent URI on every chain
+	result := MultiChainRegistration{
+		AgentURI: "ipfs://" + cid,
+		AgentIDs: map[types.ChainID]types.AgentID{},
+	}
+	globalAgentIDs := make([]string, len(registrations))
+	for idx, registration := range registrations {
+		parsedAgentID := utils.ParseAgentID(registration.AgentID)
+		globalAgentIDs[idx] = caip.FormatAgentID(
+			int64(parsedAgentID.ChainID),
+			registration.IdentityRegistry.String(),
+			parsedAgentID.TokenID,
+		)
+	}
+	for idx, agent := range agents {
+		agent.setAgentURI(flows[idx], result.AgentURI)
+		agent.registrationFile.Registrations = globalAgentIDs
+		flows[idx].Complete()
+		result.AgentIDs[agent.sdk.ChainID()] = agent.registrationFile.AgentID
+	}
 
+	// The agent takes the registration on its own chain (or the first chain)
+	if owner != a {
+		a.sdk = owner.sdk
+		a.registrationFile = owner.registrationFile
+		a.lastRegisteredWallet = owner.lastRegisteredWallet
+		a.lastRegisteredENS = owner.lastRegisteredENS
+		clear(a.dirtyMetadata)
+	}
+
+	return result
+}
+
+// registrationOn returns the agent ID of the registration of the agent in the identity
+// registry of an SDK, from the registrations of the registration file ("" when none).
+func (a *Agent) registrationOn(sdk *SDK) types.AgentID {
+	for _, registration := range a.registrationFile.Registrations {
+		agentID, err := caip.ParseAgentID(registration)
+		if err != nil || agentID.Chain != caip.EIP155ChainID(int64(sdk.ChainID())) {
+			continue
+		}
+		if types.Address(agentID.Registry).Equal(sdk.registries["IDENTITY"]) {
+			return utils.FormattedAgentID(sdk.ChainID(), agentID.TokenID.String())
+		}
+	}
+	return ""
+}
+
+// SetAgentURI sets the agent URI (used for updating the agent).
+func (a *Agent) SetAgentURI(agentURI types.URI) {
+	a.requireSigner()
+	if a.registrationFile.AgentID == "" {
+		log.Fatal("Agent must be registered before setting the agent URI")
+	}
+	a.sdk.requirePreflight("agent URI update", func(preflight *Preflight) PreflightResult {
+		return preflight.SetAgentURI(a.registrationFile.AgentID, agentURI)
+	})
+	a.setAgentURI(nil, agentURI)
 }
 
 // Transfer transfers the agent ownership to a new owner.
 func (a *Agent) Transfer(newOwner types.Address) TransferResult {
+	result, err := a.TryTransfer(newOwner)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return result
+}
 
-	// TODO: implementation
+// TryTransfer transfers the agent ownership to a new owner and returns failures as error
+// (e.g. reverts matched with errors.Is(err, ErrNotAgentOwner)).
+func (a *Agent) TryTransfer(newOwner types.Address) (TransferResult, error) {
+	if a.sdk == nil || a.sdk.IsReadOnly() {
+		return TransferResult{}, errors.New("cannot transfer agent: SDK is in read-only mode")
+	}
+	if a.registrationFile.AgentID == "" {
+		return TransferResult{}, errors.New("agent must be registered before transferring it")
+	}
+	if err := newOwner.Validate(); err != nil {
+		return TransferResult{}, fmt.Errorf("invalid new owner address: %w", err)
+	}
+	err := a.sdk.checkPreflight("agent transfer", func(preflight *Preflight) PreflightResult {
+		return preflight.TransferAgent(a.registrationFile.AgentID, newOwner)
+	})
+	if err != nil {
+		return TransferResult{}, err
+	}
 
-	return TransferResult{}
+	owner := a.sdk.GetAgentOwner(a.registrationFile.AgentID)
+	txHash, err := a.sdk.web3Client.transact(a.sdk.GetIdentityRegistry(), TransactionOptions{}, a.transferData(owner, newOwner))
+	if err == nil {
+		_, err = a.sdk.web3Client.waitForSuccess(txHash)
+	}
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("failed to transfer agent: %w", err)
+	}
+
+	return TransferResult{
+		TXHash:  txHash,
+		From:    owner,
+		To:      types.Address(newOwner.Checksum()),
+		AgentID: a.registrationFile.AgentID,
+	}, nil
 }
 
 // Private helper methods
 
-// registerWithoutURI registers the agent without a URI.
-func (a *Agent) registerWithoutURI() {
+// transferData returns the transferFrom() call data of the agent.
+func (a *Agent) transferData(owner, newOwner types.Address) []byte {
+	return identityRegistryBinding.PackTransferFrom(
+		owner.Common(),
+		newOwner.Common(),
+		utils.ParseAgentID(a.registrationFile.AgentID).TokenID,
+	)
+}
 
-	// TODO: implementation
+// requireUpdatePreflight runs the preflight checks of an agent update when enabled.
+func (a *Agent) requireUpdatePreflight() {
+	a.sdk.requirePreflight("agent update", func(preflight *Preflight) PreflightResult {
+		return preflight.UpdateAgent(a)
+	})
+}
 
+// requireSigner checks that the agent can send transactions.
+func (a *Agent) requireSigner() {
+	if a.sdk == nil || a.sdk.IsReadOnly() {
+		log.Fatal("Cannot register agent: SDK is in read-only mode.")
+	}
 }
 
-// registerWithURI registers the agent with a URI.
-func (a *Agent) registerWithURI(agentURI types.URI) types.RegistrationFile {
+// flowKey returns the key of the registration flows of the agent in the journal:
+// the agent ID once registered, otherwise a hash of the chain, signer and flow nonce of
+// the agent (agents with the same info do not share flows).
+func (a *Agent) flowKey() string {
+	if a.registrationFile.AgentID != "" {
+		return a.registrationFile.AgentID
+	}
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf(
+		"%d:%s:%s",
+		a.sdk.ChainID(),
+		a.sdk.web3Client.Address(),
+		a.FlowNonce(),
+	))).Hex()
+}
 
-	// TODO: implementation
+// registerWithoutURI registers the agent without a URI.
+func (a *Agent) registerWithoutURI(flow *JournalFlow) {
+	a.register(flow, a.registerData(""))
+}
+
+// registerWithURI registers the agent with a URI.
+func (a *Agent) registerWithURI(flow *JournalFlow, agentURI types.URI) types.RegistrationFile {
+	a.register(flow, a.registerData(agentURI))
+	a.registrationFile.AgentURI = agentURI
 
 	return a.registrationFile
 }
 
+// registerData returns the register() call data of the agent (the overload without
+// arguments without URI and metadata).
+func (a *Agent) registerData(agentURI types.URI) []byte {
+	metadata := a.metadataEntries(a.collectMetadataForRegistration())
+	if agentURI == "" && len(metadata) == 0 {
+		return identityRegistryBinding.PackRegister()
+	}
+	return identityRegistryBinding.PackRegister1(agentURI, metadata)
+}
+
+// register executes the register step of a flow and sets the agent ID. The register
+// transaction is journaled before it is broadcast, so a registration sent before a crash
+// is recovered from the receipt of the journaled transaction.
+func (a *Agent) register(flow *JournalFlow, data []byte) {
+	tokenID, err := a.transactStep(flow, "register", data, func(receipt *ethtypes.Receipt) string {
+		return a.extractAgentIDFromReceipt(receipt).String()
+	})
+	if err != nil {
+		log.Fatalf("Failed to register agent: %v", err)
+	}
+
+	a.setRegistered(tokenID)
+	flow.SetData("agentId", a.registrationFile.AgentID)
+}
+
+// setRegistered sets the agent ID of a newly registered agent, whose metadata is on chain.
+func (a *Agent) setRegistered(tokenID string) {
+	a.registrationFile.AgentID = utils.FormattedAgentID(a.sdk.ChainID(), tokenID)
+	a.registrationFile.UpdatedAt = time.Now().Unix()
+	a.lastRegisteredWallet = a.registrationFile.WalletAddress
+	a.lastRegisteredENS = a.ENSEndpoint()
+	clear(a.dirtyMetadata)
+}
+
+// setAgentURI executes the setAgentUri step of a flow.
+func (a *Agent) setAgentURI(flow *JournalFlow, agentURI types.URI) {
+	tokenID := utils.ParseAgentID(a.registrationFile.AgentID).TokenID
+	_, err := a.transactStep(flow, "setAgentUri", identityRegistryBinding.PackSetAgentUri(tokenID, agentURI), nil)
+	if err != nil {
+		log.Fatalf("Failed to set agent URI: %v", err)
+	}
+
+	a.registrationFile.AgentURI = agentURI
+	a.registrationFile.UpdatedAt = time.Now().Unix()
+}
+
 // updateMetadataOnChain updates the metadata of the agent on chain.
-func (a *Agent) updateMetadataOnChain() {
+func (a *Agent) updateMetadataOnChain(flow *JournalFlow) {
+	tokenID := utils.ParseAgentID(a.registrationFile.AgentID).TokenID
+	metadata := a.collectMetadataForRegistration()
+
+	for _, key := range a.changedMetadataKeys() {
+		data := identityRegistryBinding.PackSetMetadata(tokenID, key, metadata[key])
+		if _, err := a.transactStep(flow, "setMetadata:"+key, data, nil); err != nil {
+			log.Fatalf("Failed to update agent metadata: %v", err)
+		}
+	}
 
-	// TODO: implementation
+	a.lastRegisteredWallet = a.registrationFile.WalletAddress
+	a.lastRegisteredENS = a.ENSEndpoint()
+	clear(a.dirtyMetadata)
+}
+
+// changedMetadataKeys returns the sorted keys of the metadata changed since the last
+// registration (only the changed metadata is sent, deleted keys are set to empty values).
+func (a *Agent) changedMetadataKeys() []string {
+	keys := []string{}
+	for key := range a.dirtyMetadata {
+		keys = append(keys, key)
+	}
+	if a.registrationFile.WalletAddress != a.lastRegisteredWallet {
+		keys = append(keys, "agentWallet")
+	}
+	if a.ENSEndpoint() != a.lastRegisteredENS {
+		keys = append(keys, "agentName")
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// transactStep executes a transaction step of a flow (an identity registry call) and
+// returns its result. Steps confirmed before a crash are skipped, and transactions
+// submitted before a crash are waited for instead of being sent again (unless the node
+// dropped them or they were never broadcast). Transactions are journaled once signed,
+// before they are broadcast.
+func (a *Agent) transactStep(
+	flow *JournalFlow,
+	name string,
+	data []byte,
+	result func(receipt *ethtypes.Receipt) string,
+) (string, error) {
+	web3Client := a.sdk.web3Client
+
+	step, ok := flow.Step(name)
+	if ok && step.Status == JOURNAL_STEP_STATUS_CONFIRMED {
+		return step.Result, nil
+	}
+
+	txHash := ""
+	if ok && step.Status == JOURNAL_STEP_STATUS_SUBMITTED && web3Client.transactionExists(step.TxHash) {
+		txHash = step.TxHash
+	} else {
+		// Transactions are only sent once journaled, so that they are never sent twice
+		if err := flow.Intend(name); err != nil {
+			return "", fmt.Errorf("%s transaction not sent: %w", name, err)
+		}
+		sent, err := web3Client.transactSigned(a.sdk.GetIdentityRegistry(), TransactionOptions{}, data, func(txHash string) error {
+			return flow.Submit(name, txHash)
+		})
+		if err != nil {
+			// Reverts are final, other errors (e.g. network) leave the flow resumable
+			var revertErr *RevertError
+			if errors.As(err, &revertErr) {
+				flow.Fail(err.Error())
+			}
+			return "", fmt.Errorf("failed to send %s transaction: %w", name, err)
+		}
+		txHash = sent
+		if err := flow.Submit(name, txHash); err != nil {
+			// Broadcast already, the transaction is awaited anyway
+			log.Printf("warning: %v", err)
+		}
+	}
+
+	receipt, err := web3Client.waitForSuccess(txHash)
+	if err != nil {
+		flow.Fail(err.Error())
+		return "", fmt.Errorf("%s transaction failed: %w", name, err)
+	}
+
+	stepResult := receipt.TxHash.Hex()
+	if result != nil {
+		stepResult = result(receipt)
+	}
+	flow.Confirm(name, stepResult)
 
+	return stepResult, nil
 }
 
 // collectMetadataForRegistration collects the metadata for registration.
 func (a *Agent) collectMetadataForRegistration() map[string][]byte {
+	metadata := map[string][]byte{}
 
-	// TODO: implementation
+	for key, value := range a.registrationFile.Metadata {
+		metadata[key] = metadataBytes(value)
+	}
+	for key := range a.dirtyMetadata {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = []byte{} // deleted
+		}
+	}
 
-	return nil
+	if a.registrationFile.WalletAddress != "" {
+		metadata["agentWallet"] = a.registrationFile.WalletAddress.Common().Bytes()
+	}
+	if ens := a.ENSEndpoint(); ens != "" {
+		metadata["agentName"] = []byte(ens)
+	}
+
+	return metadata
 }
 
-// extractAgentIDFromReceipt extracts the agent ID from the receipt.
-func (a *Agent) extractAgentIDFromReceipt(receipt ethtypes.Receipt) big.Int {
+// metadataEntries converts metadata to the register() metadata entries (sorted by key,
+// deleted keys are omitted).
+func (a *Agent) metadataEntries(metadata map[string][]byte) []MetadataEntry {
+	entries := []MetadataEntry{}
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		if len(metadata[key]) > 0 {
+			entries = append(entries, MetadataEntry{Key: key, Value: metadata[key]})
+		}
+	}
+	return entries
+}
 
-	// TODO: implementation
+// extractAgentIDFromReceipt extracts the agent token ID from the receipt (Registered
+// event, or the mint of the agent).
+func (a *Agent) extractAgentIDFromReceipt(receipt *ethtypes.Receipt) *big.Int {
+	agentID, ok := a.sdk.DecodeReceipt(receipt).RegisteredAgentID()
+	if !ok {
+		log.Fatalf("Failed to extract agent ID from transaction %s", receipt.TxHash.Hex())
+	}
+	return utils.ParseAgentID(agentID).TokenID
+}
+
+// metadataBytes encodes a metadata value for the registry (strings and bytes are stored
+// as is, other values as JSON).
+func metadataBytes(value any) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			log.Fatalf("Failed to encode metadata value: %v", err)
+		}
+		return encoded
+	}
+}
 
-	return big.Int{}
+// metaStrings returns the string list stored in the endpoint metadata under the given key.
+// Lists decoded from JSON ([]any) are converted to []string.
+func metaStrings(meta map[string]any, key string) []string {
+	switch values := meta[key].(type) {
+	case []string:
+		return values
+	case []any:
+		strs := make([]string, 0, len(values))
+		for _, value := range values {
+			if str, ok := value.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	default:
+		return []string{}
+	}
 }
 
 // ...
 
+type MetadataEntry = bindings.IdentityRegistryMetadataEntry
+
+type MultiChainRegistration struct {
+	AgentURI types.URI
+	AgentIDs map[types.ChainID]types.AgentID
+}
+
 type TransferResult struct {
 	TXHash  string
 	From    types.Address
@@ -1,6 +1,9 @@
 package core
 
-import "github.com/ryanchristo/agent0-go/sdk/types"
+import (
+	"github.com/ryanchristo/agent0-go/sdk/bindings"
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
 
 // ERC-721 ABI includes the minimal required functions of an ERC-721 contract.
 var ERC721_ABI = `[
@@ -83,314 +86,26 @@ var ERC721_URI_STORAGE_ABI = `[
 ]`
 
 // IDENTITY REGISTRY ABI includes the functions for the identity registry contract.
-var IDENTITY_REGISTRY_ABI = `[
-	{
-		"inputs": [{"internalType": "uint256", "name": "tokenId", "type": "uint256"}],
-		"name": "ownerOf",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "address", "name": "owner", "type": "address"},
-			{"internalType": "address", "name": "operator", "type": "address"}
-		],
-		"name": "isApprovedForAll",
-		"outputs": [{"internalType": "bool", "name": "", "type": "bool"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [{"internalType": "uint256", "name": "tokenId", "type": "uint256"}],
-		"name": "getApproved",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "address", "name": "from", "type": "address"},
-			{"internalType": "address", "name": "to", "type": "address"},
-			{"internalType": "uint256", "name": "tokenId", "type": "uint256"}
-		],
-		"name": "transferFrom",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "address", "name": "to", "type": "address"},
-			{"internalType": "bool", "name": "approved", "type": "bool"}
-		],
-		"name": "setApprovalForAll",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "tokenId", "type": "uint256"},
-			{"internalType": "address", "name": "to", "type": "address"}
-		],
-		"name": "approve",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [{"internalType": "uint256", "name": "tokenId", "type": "uint256"}],
-		"name": "tokenURI",
-		"outputs": [{"internalType": "string", "name": "", "type": "string"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "tokenId", "type": "uint256"},
-			{"internalType": "string", "name": "_tokenURI", "type": "string"}
-		],
-		"name": "setTokenURI",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "register",
-		"outputs": [{"internalType": "uint256", "name": "agentId", "type": "uint256"}],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [{"internalType": "string", "name": "tokenUri", "type": "string"}],
-		"name": "register",
-		"outputs": [{"internalType": "uint256", "name": "agentId", "type": "uint256"}],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "string", "name": "tokenUri", "type": "string"},
-			{
-				"components": [
-					{"internalType": "string", "name": "key", "type": "string"},
-					{"internalType": "bytes", "name": "value", "type": "bytes"}
-				],
-				"internalType": "struct IdentityRegistry.MetadataEntry[]",
-				"name": "metadata",
-				"type": "tuple[]"
-			}
-		],
-		"name": "register",
-		"outputs": [{"internalType": "uint256", "name": "agentId", "type": "uint256"}],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "string", "name": "key", "type": "string"}
-		],
-		"name": "getMetadata",
-		"outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "string", "name": "key", "type": "string"},
-			{"internalType": "bytes", "name": "value", "type": "bytes"}
-		],
-		"name": "setMetadata",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "string", "name": "newUri", "type": "string"}
-		],
-		"name": "setAgentUri",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{"indexed": true, "internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"indexed": false, "internalType": "string", "name": "tokenURI", "type": "string"},
-			{"indexed": true, "internalType": "address", "name": "owner", "type": "address"}
-		],
-		"name": "Registered",
-		"type": "event"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{"indexed": true, "internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"indexed": true, "internalType": "string", "name": "indexedKey", "type": "string"},
-			{"indexed": false, "internalType": "string", "name": "key", "type": "string"},
-			{"indexed": false, "internalType": "bytes", "name": "value", "type": "bytes"}
-		],
-		"name": "MetadataSet",
-		"type": "event"
-	}
-]`
+var IDENTITY_REGISTRY_ABI = bindings.IdentityRegistryMetaData.ABI
 
 // REPUTATION REGISTRY ABI includes the functions for the reputation registry contract.
-var REPUTATION_REGISTRY_ABI = `[
-	{
-		"inputs": [],
-		"name": "getIdentityRegistry",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "uint8", "name": "score", "type": "uint8"},
-			{"internalType": "bytes32", "name": "tag1", "type": "bytes32"},
-			{"internalType": "bytes32", "name": "tag2", "type": "bytes32"},
-			{"internalType": "string", "name": "feedbackUri", "type": "string"},
-			{"internalType": "bytes32", "name": "feedbackHash", "type": "bytes32"},
-			{"internalType": "bytes", "name": "feedbackAuth", "type": "bytes"}
-		],
-		"name": "giveFeedback",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "uint64", "name": "feedbackIndex", "type": "uint64"}
-		],
-		"name": "revokeFeedback",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "address", "name": "clientAddress", "type": "address"},
-			{"internalType": "uint64", "name": "feedbackIndex", "type": "uint64"},
-			{"internalType": "string", "name": "responseUri", "type": "string"},
-			{"internalType": "bytes32", "name": "responseHash", "type": "bytes32"}
-		],
-		"name": "appendResponse",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "address", "name": "clientAddress", "type": "address"}
-		],
-		"name": "getLastIndex",
-		"outputs": [{"internalType": "uint64", "name": "", "type": "uint64"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "address", "name": "clientAddress", "type": "address"},
-			{"internalType": "uint64", "name": "index", "type": "uint64"}
-		],
-		"name": "readFeedback",
-		"outputs": [
-			{"internalType": "uint8", "name": "score", "type": "uint8"},
-			{"internalType": "bytes32", "name": "tag1", "type": "bytes32"},
-			{"internalType": "bytes32", "name": "tag2", "type": "bytes32"},
-			{"internalType": "bool", "name": "isRevoked", "type": "bool"}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "address[]", "name": "clientAddresses", "type": "address[]"},
-			{"internalType": "bytes32", "name": "tag1", "type": "bytes32"},
-			{"internalType": "bytes32", "name": "tag2", "type": "bytes32"}
-		],
-		"name": "getSummary",
-		"outputs": [
-			{"internalType": "uint64", "name": "count", "type": "uint64"},
-			{"internalType": "uint8", "name": "averageScore", "type": "uint8"}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{"indexed": true, "internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"indexed": true, "internalType": "address", "name": "clientAddress", "type": "address"},
-			{"indexed": false, "internalType": "uint8", "name": "score", "type": "uint8"},
-			{"indexed": true, "internalType": "bytes32", "name": "tag1", "type": "bytes32"},
-			{"indexed": false, "internalType": "bytes32", "name": "tag2", "type": "bytes32"},
-			{"indexed": false, "internalType": "string", "name": "feedbackUri", "type": "string"},
-			{"indexed": false, "internalType": "bytes32", "name": "feedbackHash", "type": "bytes32"}
-		],
-		"name": "NewFeedback",
-		"type": "event"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{"indexed": true, "internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"indexed": true, "internalType": "address", "name": "clientAddress", "type": "address"},
-			{"indexed": true, "internalType": "uint64", "name": "feedbackIndex", "type": "uint64"}
-		],
-		"name": "FeedbackRevoked",
-		"type": "event"
-	}
-]`
+var REPUTATION_REGISTRY_ABI = bindings.ReputationRegistryMetaData.ABI
 
 // VALIDATION REGISTRY ABI includes the functions for the validation registry contract.
-var VALIDATION_REGISTRY_ABI = `[
-	{
-		"inputs": [],
-		"name": "getIdentityRegistry",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "address", "name": "validatorAddress", "type": "address"},
-			{"internalType": "uint256", "name": "agentId", "type": "uint256"},
-			{"internalType": "string", "name": "requestUri", "type": "string"},
-			{"internalType": "bytes32", "name": "requestHash", "type": "bytes32"}
-		],
-		"name": "validationRequest",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "bytes32", "name": "requestHash", "type": "bytes32"},
-			{"internalType": "uint8", "name": "response", "type": "uint8"},
-			{"internalType": "string", "name": "responseUri", "type": "string"},
-			{"internalType": "bytes32", "name": "responseHash", "type": "bytes32"},
-			{"internalType": "bytes32", "name": "tag", "type": "bytes32"}
-		],
-		"name": "validationResponse",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	}
-]`
+var VALIDATION_REGISTRY_ABI = bindings.ValidationRegistryMetaData.ABI
+
+// Typed bindings of the registries (generated from bindings/abi), used to pack the calls
+// and unpack the results and events of the registries.
+var (
+	identityRegistryBinding   = bindings.NewIdentityRegistry()
+	reputationRegistryBinding = bindings.NewReputationRegistry()
+	validationRegistryBinding = bindings.NewValidationRegistry()
+	multicall3Binding         = bindings.NewMulticall3()
+)
+
+// MULTICALL3_ADDRESS is the address of Multicall3, deployed at the same address on most
+// chains (batched reads fall back to JSON-RPC batch requests where it is not deployed).
+const MULTICALL3_ADDRESS types.Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
 
 // DEFAULT_REGISTRIES includes the default contract addresses for different chains.
 var DEFAULT_REGISTRIES = map[types.ChainID]map[string]types.Address{
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// DryRun simulates the write operations of the SDK without broadcasting transactions.
+// Every method returns the calldata, the estimated gas and fee and the decoded revert
+// reason (if any) of the transaction the matching SDK method would send.
+type DryRun struct {
+	sdk *SDK
+}
+
+// DryRun returns the dry run mode of the SDK.
+func (s *SDK) DryRun() *DryRun {
+	return &DryRun{sdk: s}
+}
+
+// Register simulates the registration of a new agent. The agent URI is empty for the
+// IPFS workflow (the URI is set after the registration file is uploaded).
+func (d *DryRun) Register(agent *Agent, agentURI types.URI) SimulationResult {
+	return d.simulate(d.sdk.registerCall(agent, agentURI))
+}
+
+// SetAgentURI simulates setting the agent URI.
+func (d *DryRun) SetAgentURI(agentID types.AgentID, agentURI types.URI) SimulationResult {
+	return d.simulate(d.sdk.setAgentURICall(agentID, agentURI))
+}
+
+// SetMetadata simulates setting an on-chain metadata entry of the agent.
+func (d *DryRun) SetMetadata(agentID types.AgentID, key string, value any) SimulationResult {
+	return d.simulate(d.sdk.setMetadataCall(agentID, key, value))
+}
+
+// GiveFeedback simulates giving feedback. The feedback file is not uploaded, so the
+// feedback URI is left empty (the hash still commits to the file when IPFS is configured).
+func (d *DryRun) GiveFeedback(agentID types.AgentID, feedbackFile map[string]any, feedbackAuth string) SimulationResult {
+	return d.simulate(d.sdk.giveFeedbackCall(agentID, feedbackFile, feedbackAuth, false))
+}
+
+// RevokeFeedback simulates revoking feedback.
+func (d *DryRun) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) SimulationResult {
+	return d.simulate(d.sdk.revokeFeedbackCall(agentID, feedbackIndex))
+}
+
+// Transfer simulates transferring the agent ownership to a new owner.
+func (d *DryRun) Transfer(agentID types.AgentID, newOwner types.Address) SimulationResult {
+	return d.simulate(d.sdk.transferCall(agentID, newOwner))
+}
+
+// simulate simulates a contract call.
+func (d *DryRun) simulate(call contractCall) SimulationResult {
+	return d.sdk.web3Client.simulate(call.contract, TransactionOptions{}, call.method, call.data)
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// FeeStrategy computes the fee fields of a transaction.
+type FeeStrategy interface {
+	// Fees returns the fees of the next transaction.
+	Fees(ctx context.Context, provider *ethclient.Client) (TransactionFees, error)
+}
+
+// TransactionFees are the fee fields of a transaction. Either GasPrice is set (legacy
+// transaction) or GasFeeCap and GasTipCap are set (EIP-1559 dynamic fee transaction).
+type TransactionFees struct {
+	GasPrice  *big.Int
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+}
+
+// IsDynamic checks if the fees are for a dynamic fee transaction.
+func (f TransactionFees) IsDynamic() bool {
+	return f.GasFeeCap != nil
+}
+
+// MaxFeePerGas returns the maximum price paid per unit of gas.
+func (f TransactionFees) MaxFeePerGas() *big.Int {
+	if f.IsDynamic() {
+		return f.GasFeeCap
+	}
+	return f.GasPrice
+}
+
+// DEFAULT_FEE_STRATEGIES includes the default fee strategies for different chains.
+// Chains without a default use DefaultFeeStrategy.
+var DEFAULT_FEE_STRATEGIES = map[types.ChainID]FeeStrategy{
+	11155111: &DynamicFeeStrategy{BaseFeeMultiplier: 2},
+	84532: &DynamicFeeStrategy{
+		// Base Sepolia (low and stable base fee)
+		BaseFeeMultiplier: 2,
+		MinPriorityFee:    big.NewInt(1_000_000), // 0.001 gwei
+	},
+	80002: &DynamicFeeStrategy{
+		// Polygon Amoy (enforces a minimum priority fee of 25 gwei)
+		BaseFeeMultiplier: 2,
+		MinPriorityFee:    big.NewInt(25 * params.GWei),
+	},
+}
+
+// DefaultFeeStrategy returns the default fee strategy for a chain.
+func DefaultFeeStrategy(chainID types.ChainID) FeeStrategy {
+	if strategy, ok := DEFAULT_FEE_STRATEGIES[chainID]; ok {
+		return strategy
+	}
+	return &DynamicFeeStrategy{BaseFeeMultiplier: 2}
+}
+
+// LegacyFeeStrategy uses the suggested gas price of the node (legacy transactions).
+type LegacyFeeStrategy struct {
+	// Multiplier is applied to the suggested gas price (default 1).
+	Multiplier float64
+}
+
+// Fees returns the fees of the next transaction.
+func (s *LegacyFeeStrategy) Fees(ctx context.Context, provider *ethclient.Client) (TransactionFees, error) {
+	gasPrice, err := provider.SuggestGasPrice(ctx)
+	if err != nil {
+		return TransactionFees{}, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+
+	return TransactionFees{GasPrice: multiplyFee(gasPrice, s.Multiplier)}, nil
+}
+
+// DynamicFeeStrategy builds EIP-1559 fees from the latest base fee:
+// GasFeeCap = BaseFee * BaseFeeMultiplier + GasTipCap.
+// Chains without a base fee fall back to the suggested gas price (legacy transactions).
+type DynamicFeeStrategy struct {
+	// BaseFeeMultiplier is applied to the latest base fee (default 2, which covers
+	// six consecutive full blocks).
+	BaseFeeMultiplier float64
+
+	// PriorityFee is the fixed priority fee (the suggested priority fee is used when unset).
+	PriorityFee *big.Int
+
+	// MinPriorityFee is the lower bound of the suggested priority fee.
+	MinPriorityFee *big.Int
+}
+
+// Fees returns the fees of the next transaction.
+func (s *DynamicFeeStrategy) Fees(ctx context.Context, provider *ethclient.Client) (TransactionFees, error) {
+	header, err := provider.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return TransactionFees{}, fmt.Errorf("failed to get latest block header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return (&LegacyFeeStrategy{}).Fees(ctx, provider)
+	}
+
+	gasTipCap := s.PriorityFee
+	if gasTipCap == nil {
+		if gasTipCap, err = provider.SuggestGasTipCap(ctx); err != nil {
+			return TransactionFees{}, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+		}
+		if s.MinPriorityFee != nil && gasTipCap.Cmp(s.MinPriorityFee) < 0 {
+			gasTipCap = s.MinPriorityFee
+		}
+	}
+
+	multiplier := s.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	gasFeeCap := multiplyFee(header.BaseFee, multiplier)
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+
+	return TransactionFees{
+		GasFeeCap: gasFeeCap,
+		GasTipCap: new(big.Int).Set(gasTipCap),
+	}, nil
+}
+
+// FixedFeeStrategy always uses the same fees. Set GasPrice for legacy transactions or
+// MaxFeePerGas and MaxPriorityFeePerGas for dynamic fee transactions.
+type FixedFeeStrategy struct {
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// Fees returns the fees of the next transaction.
+func (s *FixedFeeStrategy) Fees(ctx context.Context, provider *ethclient.Client) (TransactionFees, error) {
+	return fixedFees(s.GasPrice, s.MaxFeePerGas, s.MaxPriorityFeePerGas)
+}
+
+// fixedFees validates and returns fixed fees.
+func fixedFees(gasPrice, maxFeePerGas, maxPriorityFeePerGas *big.Int) (TransactionFees, error) {
+	if gasPrice != nil && gasPrice.Sign() > 0 {
+		if maxFeePerGas != nil && maxFeePerGas.Sign() > 0 {
+			return TransactionFees{}, errors.New("gas price cannot be combined with max fee per gas")
+		}
+		return TransactionFees{GasPrice: new(big.Int).Set(gasPrice)}, nil
+	}
+
+	if maxFeePerGas == nil || maxFeePerGas.Sign() == 0 {
+		return TransactionFees{}, errors.New("either gas price or max fee per gas is required")
+	}
+
+	gasTipCap := new(big.Int)
+	if maxPriorityFeePerGas != nil {
+		gasTipCap.Set(maxPriorityFeePerGas)
+	}
+	if gasTipCap.Cmp(maxFeePerGas) > 0 {
+		return TransactionFees{}, fmt.Errorf("max priority fee per gas (%s) exceeds max fee per gas (%s)", gasTipCap, maxFeePerGas)
+	}
+
+	return TransactionFees{GasFeeCap: new(big.Int).Set(maxFeePerGas), GasTipCap: gasTipCap}, nil
+}
+
+// multiplyFee multiplies a fee by a floating point multiplier (a zero multiplier is ignored).
+func multiplyFee(fee *big.Int, multiplier float64) *big.Int {
+	if multiplier == 0 || multiplier == 1 {
+		return new(big.Int).Set(fee)
+	}
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(multiplier)).Int(nil)
+	return result
+}
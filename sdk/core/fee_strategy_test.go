@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// newFakeFeeRPC serves the latest block header with the given base fee (nil before
+// London), the suggested gas price and the suggested priority fee.
+func newFakeFeeRPC(t *testing.T, baseFee, gasPrice, tipCap int64) *ethclient.Client {
+	return newFakeRPC(t, func(method string, params []json.RawMessage) (any, error) {
+		switch method {
+		case "eth_getBlockByNumber":
+			header := &ethtypes.Header{Number: big.NewInt(1), Difficulty: new(big.Int)}
+			if baseFee >= 0 {
+				header.BaseFee = big.NewInt(baseFee)
+			}
+			return header, nil
+		case "eth_gasPrice":
+			return (*hexutil.Big)(big.NewInt(gasPrice)), nil
+		case "eth_maxPriorityFeePerGas":
+			return (*hexutil.Big)(big.NewInt(tipCap)), nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	})
+}
+
+func TestFeeStrategies(t *testing.T) {
+	london := newFakeFeeRPC(t, 100, 150, 10)
+	preLondon := newFakeFeeRPC(t, -1, 150, 10)
+
+	tests := []struct {
+		name     string
+		strategy FeeStrategy
+		provider *ethclient.Client
+		want     TransactionFees
+	}{
+		{
+			name:     "legacy",
+			strategy: &LegacyFeeStrategy{},
+			provider: london,
+			want:     TransactionFees{GasPrice: big.NewInt(150)},
+		},
+		{
+			name:     "legacy with multiplier",
+			strategy: &LegacyFeeStrategy{Multiplier: 1.5},
+			provider: london,
+			want:     TransactionFees{GasPrice: big.NewInt(225)},
+		},
+		{
+			name:     "dynamic with default multiplier",
+			strategy: &DynamicFeeStrategy{},
+			provider: london,
+			want:     TransactionFees{GasFeeCap: big.NewInt(210), GasTipCap: big.NewInt(10)},
+		},
+		{
+			name:     "dynamic with multiplier",
+			strategy: &DynamicFeeStrategy{BaseFeeMultiplier: 3},
+			provider: london,
+			want:     TransactionFees{GasFeeCap: big.NewInt(310), GasTipCap: big.NewInt(10)},
+		},
+		{
+			name:     "dynamic with fixed priority fee",
+			strategy: &DynamicFeeStrategy{PriorityFee: big.NewInt(5), MinPriorityFee: big.NewInt(50)},
+			provider: london,
+			want:     TransactionFees{GasFeeCap: big.NewInt(205), GasTipCap: big.NewInt(5)},
+		},
+		{
+			name:     "dynamic with min priority fee",
+			strategy: &DynamicFeeStrategy{MinPriorityFee: big.NewInt(50)},
+			provider: london,
+			want:     TransactionFees{GasFeeCap: big.NewInt(250), GasTipCap: big.NewInt(50)},
+		},
+		{
+			name:     "dynamic without base fee",
+			strategy: &DynamicFeeStrategy{BaseFeeMultiplier: 3},
+			provider: preLondon,
+			want:     TransactionFees{GasPrice: big.NewInt(150)},
+		},
+		{
+			name:     "fixed legacy",
+			strategy: &FixedFeeStrategy{GasPrice: big.NewInt(7)},
+			want:     TransactionFees{GasPrice: big.NewInt(7)},
+		},
+		{
+			name:     "fixed dynamic",
+			strategy: &FixedFeeStrategy{MaxFeePerGas: big.NewInt(9), MaxPriorityFeePerGas: big.NewInt(2)},
+			want:     TransactionFees{GasFeeCap: big.NewInt(9), GasTipCap: big.NewInt(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees, err := tt.strategy.Fees(context.Background(), tt.provider)
+			if err != nil {
+				t.Fatalf("Fees error: %v", err)
+			}
+			if !equalFee(fees.GasPrice, tt.want.GasPrice) ||
+				!equalFee(fees.GasFeeCap, tt.want.GasFeeCap) ||
+				!equalFee(fees.GasTipCap, tt.want.GasTipCap) {
+				t.Errorf("Fees = %+v, want %+v", fees, tt.want)
+			}
+			if fees.IsDynamic() != (tt.want.GasFeeCap != nil) {
+				t.Errorf("IsDynamic = %v, want %v", fees.IsDynamic(), tt.want.GasFeeCap != nil)
+			}
+		})
+	}
+}
+
+func TestFixedFeeStrategyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *FixedFeeStrategy
+	}{
+		{name: "no fees", strategy: &FixedFeeStrategy{}},
+		{name: "zero max fee", strategy: &FixedFeeStrategy{MaxFeePerGas: new(big.Int), MaxPriorityFeePerGas: big.NewInt(1)}},
+		{name: "gas price and max fee", strategy: &FixedFeeStrategy{GasPrice: big.NewInt(1), MaxFeePerGas: big.NewInt(2)}},
+		{name: "priority fee above max fee", strategy: &FixedFeeStrategy{MaxFeePerGas: big.NewInt(2), MaxPriorityFeePerGas: big.NewInt(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if fees, err := tt.strategy.Fees(context.Background(), nil); err == nil {
+				t.Errorf("Fees = %+v, want an error", fees)
+			}
+		})
+	}
+}
+
+func TestDefaultFeeStrategy(t *testing.T) {
+	amoy, ok := DefaultFeeStrategy(80002).(*DynamicFeeStrategy)
+	if !ok || amoy.MinPriorityFee == nil || amoy.MinPriorityFee.Cmp(big.NewInt(25_000_000_000)) != 0 {
+		t.Errorf("DefaultFeeStrategy(80002) = %+v, want a 25 gwei minimum priority fee", amoy)
+	}
+
+	other, ok := DefaultFeeStrategy(1).(*DynamicFeeStrategy)
+	if !ok || other.BaseFeeMultiplier != 2 || other.PriorityFee != nil || other.MinPriorityFee != nil {
+		t.Errorf("DefaultFeeStrategy(1) = %+v, want the dynamic fee strategy with multiplier 2", other)
+	}
+}
+
+// equalFee checks if two optional fees are equal.
+func equalFee(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Cmp(b) == 0
+}
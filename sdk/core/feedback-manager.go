@@ -1,7 +1,22 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ryanchristo/agent0-go/sdk/types"
 	"github.com/ryanchristo/agent0-go/sdk/utils"
@@ -30,6 +45,8 @@ type FeedbackManager struct {
 
 	getSubgraphClientForChain func(chainID types.ChainID) *SubgraphClient
 	defaultChainID            types.ChainID
+
+	mu sync.RWMutex // guards the registries set after initialization
 }
 
 // NewFeedbackManager creates a new FeedbackManager instance.
@@ -61,14 +78,25 @@ func (f *FeedbackManager) SetSubgraphClientGetter(
 
 // SetReputationRegistry sets the reputation registry contract (for lazy initialization).
 func (f *FeedbackManager) SetReputationRegistry(registry *Contract) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.reputationRegistry = registry
 }
 
 // SetIdentityRegistry sets the identity registry contract (for lazy initialization).
 func (f *FeedbackManager) SetIdentityRegistry(registry *Contract) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.identityRegistry = registry
 }
 
+// registries returns the reputation and identity registry contracts.
+func (f *FeedbackManager) registries() (*Contract, *Contract) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.reputationRegistry, f.identityRegistry
+}
+
 // SignFeedbackAuth signs the feedback authorization information for a client.
 func (f *FeedbackManager) SignFeedbackAuth(
 	agentID types.AgentID,
@@ -80,9 +108,94 @@ func (f *FeedbackManager) SignFeedbackAuth(
 		expiryHours = utils.DEFAULTS["FEEDBACK_EXPIRY_HOURS"]
 	}
 
-	// TODO: implementation
+	reputationRegistry, identityRegistry := f.registries()
+	if identityRegistry == nil {
+		log.Fatal("Identity registry not available")
+	}
 
-	return "0x"
+	tokenID := utils.ParseAgentID(agentID).TokenID
+
+	// Default to allowing the next feedback index of the client
+	if indexLimit == 0 {
+		if reputationRegistry == nil {
+			log.Fatal("Reputation registry not available")
+		}
+		indexLimit = int64(f.getLastIndex(reputationRegistry, tokenID, clientAddress)) + 1
+	}
+
+	expiry := time.Now().Unix() + expiryHours*3600
+
+	auth := FeedbackAuth{
+		AgentID:          *tokenID,
+		ClientAddress:    clientAddress,
+		IndexLimit:       *big.NewInt(indexLimit),
+		Expiry:           *big.NewInt(expiry),
+		ChainID:          *f.web3Client.ChainID.BigInt(),
+		IdentityRegistry: types.AddressFromCommon(identityRegistry.Address()),
+		SignerAddress:    f.web3Client.GetAddress(),
+	}
+
+	encoded := f.web3Client.EncodeFeedbackAuth(
+		auth.AgentID,
+		auth.ClientAddress,
+		auth.IndexLimit,
+		auth.Expiry,
+		auth.ChainID,
+		auth.IdentityRegistry,
+		auth.SignerAddress,
+	)
+
+	// Sign the hash of the encoded data as an EIP-191 personal message
+	messageHash := crypto.Keccak256(hexutil.MustDecode(encoded))
+	signature := f.web3Client.SignText(messageHash)
+
+	// The authorization is the encoded data followed by the signature
+	return encoded + strings.TrimPrefix(signature, "0x")
+}
+
+// ParseFeedbackAuth decodes a signed feedback authorization (see SignFeedbackAuth) and
+// checks that it is signed by its signer address. The other fields (agent, client,
+// expiry, chain and registry) are not checked.
+func ParseFeedbackAuth(feedbackAuth string) (FeedbackAuth, error) {
+	data, err := hexutil.Decode(feedbackAuth)
+	if err != nil {
+		return FeedbackAuth{}, fmt.Errorf("%w: %v", ErrFeedbackAuthInvalid, err)
+	}
+
+	arguments := feedbackAuthArguments()
+	encodedLength := len(arguments) * 32
+	if len(data) != encodedLength+crypto.SignatureLength {
+		return FeedbackAuth{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrFeedbackAuthInvalid, encodedLength+crypto.SignatureLength, len(data))
+	}
+	encoded, signature := data[:encodedLength], slices.Clone(data[encodedLength:])
+
+	values, err := arguments.Unpack(encoded)
+	if err != nil {
+		return FeedbackAuth{}, fmt.Errorf("%w: %v", ErrFeedbackAuthInvalid, err)
+	}
+	auth := FeedbackAuth{
+		AgentID:          *values[0].(*big.Int),
+		ClientAddress:    types.AddressFromCommon(values[1].(common.Address)),
+		IndexLimit:       *values[2].(*big.Int),
+		Expiry:           *values[3].(*big.Int),
+		ChainID:          *values[4].(*big.Int),
+		IdentityRegistry: types.AddressFromCommon(values[5].(common.Address)),
+		SignerAddress:    types.AddressFromCommon(values[6].(common.Address)),
+	}
+
+	// The signature is an EIP-191 personal signature of the hash of the encoded data
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash(crypto.Keccak256(encoded)), signature)
+	if err != nil {
+		return FeedbackAuth{}, fmt.Errorf("%w: %v", ErrFeedbackAuthInvalid, err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != auth.SignerAddress.Common() {
+		return FeedbackAuth{}, fmt.Errorf("%w: signed by %s instead of %s", ErrFeedbackAuthInvalid, signer.Hex(), auth.SignerAddress)
+	}
+
+	return auth, nil
 }
 
 // PrepareFeedback prepares a feedback file for submission.
@@ -105,41 +218,222 @@ func (f *FeedbackManager) PrepareFeedback(
 	return map[string]any{}
 }
 
-// GiveFeedback submits feedback (maps 8004 endpoint).
+// GiveFeedback submits feedback (maps 8004 endpoint) and waits for it to be mined.
+// Reverts are returned as RevertError, matched with errors.Is (e.g. ErrNotAgentOwner).
 func (f *FeedbackManager) GiveFeedback(
 	agentID types.AgentID,
 	feedbackFile map[string]any,
 	idem types.IdemKey,
 	feedbackAuth string,
-) Feedback {
+) (Feedback, error) {
+	reputationRegistry, _ := f.registries()
+	if reputationRegistry == nil {
+		return Feedback{}, errors.New("reputation registry not available")
+	}
 
-	// TODO: implementation
+	tokenID := utils.ParseAgentID(agentID).TokenID
+	score, tags, err := f.parseFeedbackFile(feedbackFile)
+	if err != nil {
+		return Feedback{}, err
+	}
 
-	return Feedback{}
+	// Store the feedback file on IPFS when available (the hash commits to the stored file)
+	feedbackURI := ""
+	feedbackHash := [32]byte{}
+	if f.ipfsClient != nil {
+		feedbackJSON := f.feedbackJSON(feedbackFile)
+		feedbackURI = "ipfs://" + f.ipfsClient.Add(string(feedbackJSON))
+		feedbackHash = crypto.Keccak256Hash(feedbackJSON)
+	}
+
+	data, err := f.giveFeedbackData(agentID, score, tags, feedbackURI, feedbackHash, feedbackAuth)
+	if err != nil {
+		return Feedback{}, err
+	}
+
+	txHash, err := f.web3Client.transact(reputationRegistry, TransactionOptions{}, data)
+	if err != nil {
+		return Feedback{}, fmt.Errorf("failed to give feedback: %w", err)
+	}
+	receipt, err := f.web3Client.waitForSuccess(txHash)
+	if err != nil {
+		return Feedback{}, fmt.Errorf("failed to give feedback: %w", err)
+	}
+
+	clientAddress := f.web3Client.GetAddress()
+	feedbackAgentID := utils.FormattedAgentID(f.web3Client.ChainID, tokenID.String())
+	events := DecodeReceiptEvents(receipt, f.web3Client.ChainID, common.Address{}, reputationRegistry.Address())
+	eventIdx := slices.IndexFunc(events.NewFeedback, func(event NewFeedbackEvent) bool {
+		return event.AgentID == feedbackAgentID && event.ClientAddress.Equal(clientAddress)
+	})
+	if eventIdx < 0 {
+		return Feedback{}, fmt.Errorf("feedback transaction %s did not emit NewFeedback", receipt.TxHash.Hex())
+	}
+
+	feedbackIndex := f.feedbackIndexAt(reputationRegistry, tokenID, clientAddress, receipt.BlockNumber, events.NewFeedback[eventIdx].LogIndex)
+
+	return Feedback{
+		ID:            []string{agentID, clientAddress.Lower(), strconv.FormatUint(feedbackIndex, 10)},
+		FeedbackIndex: int64(feedbackIndex),
+		Score:         score,
+		Tags:          tags,
+	}, nil
 }
 
-// GetFeedback gets a single feedback entry (currently only supports blockchain query).
-func (f *FeedbackManager) GetFeedback(
+// parseFeedbackFile validates a feedback file and returns its score and tags.
+func (f *FeedbackManager) parseFeedbackFile(feedbackFile map[string]any) (int64, []string, error) {
+	score := feedbackScore(feedbackFile["score"])
+	if score < 0 || score > 100 {
+		return 0, nil, fmt.Errorf("feedback score must be between 0 and 100, got %v", feedbackFile["score"])
+	}
+	return score, metaStrings(feedbackFile, "tags"), nil
+}
+
+// giveFeedbackData returns the giveFeedback() call data (the first two tags are stored on chain).
+func (f *FeedbackManager) giveFeedbackData(
 	agentID types.AgentID,
+	score int64,
+	tags []string,
+	feedbackURI types.URI,
+	feedbackHash [32]byte,
+	feedbackAuth string,
+) ([]byte, error) {
+	tag1, tag2 := "", ""
+	if len(tags) > 0 {
+		tag1 = tags[0]
+	}
+	if len(tags) > 1 {
+		tag2 = tags[1]
+	}
+
+	auth, err := hexutil.Decode(feedbackAuth)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFeedbackAuthInvalid, err)
+	}
+
+	return reputationRegistryBinding.PackGiveFeedback(
+		utils.ParseAgentID(agentID).TokenID,
+		uint8(score),
+		f.stringToBytes32(tag1),
+		f.stringToBytes32(tag2),
+		feedbackURI,
+		feedbackHash,
+		auth,
+	), nil
+}
+
+// getLastIndex returns the last feedback index of a client for an agent.
+func (f *FeedbackManager) getLastIndex(reputationRegistry *Contract, tokenID *big.Int, clientAddress types.Address) uint64 {
+	return f.getLastIndexAt(reputationRegistry, tokenID, clientAddress, nil)
+}
+
+// getLastIndexAt returns the last feedback index of a client for an agent at a block
+// (latest block when nil).
+func (f *FeedbackManager) getLastIndexAt(
+	reputationRegistry *Contract,
+	tokenID *big.Int,
 	clientAddress types.Address,
-	feedbackIndex int64,
-) Feedback {
+	blockNumber *big.Int,
+) uint64 {
+	lastIndex, err := callContractAt(
+		reputationRegistry,
+		blockNumber,
+		reputationRegistryBinding.PackGetLastIndex(tokenID, clientAddress.Common()),
+		reputationRegistryBinding.UnpackGetLastIndex,
+	)
+	if err != nil {
+		log.Fatalf("Failed to get last feedback index: %v", err)
+	}
+	return lastIndex
+}
 
-	// TODO: implementation
+// feedbackIndexAt returns the index of the feedback of a client emitted by a NewFeedback
+// log (NewFeedback does not include the index): the last index of the client before the
+// block, plus the position of the log among the NewFeedback logs of the client for the
+// agent in the block (several feedbacks of a client may be mined in the same block).
+func (f *FeedbackManager) feedbackIndexAt(
+	reputationRegistry *Contract,
+	tokenID *big.Int,
+	clientAddress types.Address,
+	blockNumber *big.Int,
+	logIndex uint,
+) uint64 {
+	previousBlock := new(big.Int).Sub(blockNumber, big.NewInt(1))
+	feedbackIndex := f.getLastIndexAt(reputationRegistry, tokenID, clientAddress, previousBlock)
+
+	logs := f.web3Client.GetEvents(
+		reputationRegistry,
+		"NewFeedback",
+		blockNumber.Int64(),
+		blockNumber.Int64(),
+		[]any{tokenID},
+		[]any{clientAddress.Common()},
+	)
+	for _, l := range logs {
+		if !l.Removed && l.Index <= logIndex {
+			feedbackIndex++
+		}
+	}
+	return feedbackIndex
+}
 
-	return Feedback{}
+// feedbackJSON encodes a feedback file for storage.
+func (f *FeedbackManager) feedbackJSON(feedbackFile map[string]any) []byte {
+	feedbackJSON, err := json.Marshal(feedbackFile)
+	if err != nil {
+		log.Fatalf("Failed to marshal feedback file: %v", err)
+	}
+	return feedbackJSON
 }
 
-// getFeedbackFromBlockchain gets a single feedback entry from the blockchain.
-func (f *FeedbackManager) getFeedbackFromBlockchain(
+// GetFeedback gets a single feedback entry (currently only supports blockchain query).
+func (f *FeedbackManager) GetFeedback(
 	agentID types.AgentID,
 	clientAddress types.Address,
 	feedbackIndex int64,
 ) Feedback {
+	return f.GetFeedbacks([]FeedbackRef{{agentID, clientAddress, feedbackIndex}})[0]
+}
 
-	// TODO: implementation
+// GetFeedbacks gets feedback entries in the order of the references (currently only
+// supports blockchain query).
+func (f *FeedbackManager) GetFeedbacks(refs []FeedbackRef) []Feedback {
+	return f.getFeedbacksFromBlockchain(refs)
+}
 
-	return Feedback{}
+// getFeedbacksFromBlockchain gets feedback entries from the blockchain, reading them in
+// batches.
+func (f *FeedbackManager) getFeedbacksFromBlockchain(refs []FeedbackRef) []Feedback {
+	reputationRegistry, _ := f.registries()
+	if reputationRegistry == nil {
+		log.Fatal("Reputation registry not available")
+	}
+
+	calls := make([]ReadCall, len(refs))
+	for idx, ref := range refs {
+		calls[idx] = ReadCall{reputationRegistry, reputationRegistryBinding.PackReadFeedback(
+			utils.ParseAgentID(ref.AgentID).TokenID,
+			ref.ClientAddress.Common(),
+			uint64(ref.FeedbackIndex),
+		)}
+	}
+	outputs, errs := batchRead(f.web3Client, calls, reputationRegistryBinding.UnpackReadFeedback)
+
+	feedbacks := make([]Feedback, len(refs))
+	for idx, ref := range refs {
+		if errs[idx] != nil {
+			log.Fatalf("Failed to read feedback %d of agent %s: %v", ref.FeedbackIndex, ref.AgentID, errs[idx])
+		}
+		output := outputs[idx]
+		feedbacks[idx] = Feedback{
+			ID:            []string{ref.AgentID, ref.ClientAddress.Lower(), strconv.FormatInt(ref.FeedbackIndex, 10)},
+			FeedbackIndex: ref.FeedbackIndex,
+			Score:         int64(output.Score),
+			Tags:          f.bytes32ToTags(common.Bytes2Hex(output.Tag1[:]), common.Bytes2Hex(output.Tag2[:])),
+			IsRevoked:     output.IsRevoked,
+		}
+	}
+	return feedbacks
 }
 
 // SearchFeedback searches feedback entries with filters (uses subgraph if available).
@@ -173,42 +467,109 @@ func (f *FeedbackManager) hexBytes32ToTakes(tag1 string, tag2 string) []string {
 	return []string{}
 }
 
-// AppendResponse appends a response to feedback.
+// AppendResponse appends a response to feedback, waits for it to be mined and returns
+// the transaction hash. Reverts are returned as RevertError (see GiveFeedback).
 func (f *FeedbackManager) AppendResponse(
 	agentID types.AgentID,
 	clientAddress types.Address,
 	feedbackIndex int64,
 	responseURI types.URI,
 	responseHash string,
-) string {
+) (string, error) {
+	reputationRegistry, _ := f.registries()
+	if reputationRegistry == nil {
+		return "", errors.New("reputation registry not available")
+	}
 
-	// TODO: implementation
+	data := f.appendResponseData(agentID, clientAddress, feedbackIndex, responseURI, responseHash)
+
+	txHash, err := f.web3Client.transact(reputationRegistry, TransactionOptions{}, data)
+	if err == nil {
+		_, err = f.web3Client.waitForSuccess(txHash)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to append response: %w", err)
+	}
+
+	return txHash, nil
+}
 
-	return ""
+// appendResponseData returns the appendResponse() call data.
+func (f *FeedbackManager) appendResponseData(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+	responseURI types.URI,
+	responseHash string,
+) []byte {
+	return reputationRegistryBinding.PackAppendResponse(
+		utils.ParseAgentID(agentID).TokenID,
+		clientAddress.Common(),
+		uint64(feedbackIndex),
+		responseURI,
+		common.HexToHash(responseHash),
+	)
 }
 
-// RevokeFeedback revokes feedback.
-func (f *FeedbackManager) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) string {
+// RevokeFeedback revokes feedback, waits for it to be mined and returns the transaction
+// hash. Reverts are returned as RevertError (see GiveFeedback).
+func (f *FeedbackManager) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) (string, error) {
+	reputationRegistry, _ := f.registries()
+	if reputationRegistry == nil {
+		return "", errors.New("reputation registry not available")
+	}
 
-	// TODO: implementation
+	tokenID := utils.ParseAgentID(agentID).TokenID
 
-	return ""
-}
+	data := reputationRegistryBinding.PackRevokeFeedback(tokenID, uint64(feedbackIndex))
 
-// stringToBytes32 converts a string to bytes32 for blockchain storage.
-func (f *FeedbackManager) stringToBytes32(text string) string {
+	txHash, err := f.web3Client.transact(reputationRegistry, TransactionOptions{}, data)
+	if err == nil {
+		_, err = f.web3Client.waitForSuccess(txHash)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to revoke feedback: %w", err)
+	}
 
-	// TODO: implementation
+	return txHash, nil
+}
 
-	return ""
+// stringToBytes32 converts a string to bytes32 for blockchain storage
+// (right padded with zeros, truncated to 32 bytes).
+func (f *FeedbackManager) stringToBytes32(text string) [32]byte {
+	var result [32]byte
+	copy(result[:], text)
+	return result
+}
+
+// feedbackScore converts a feedback file score to an integer (-1 if invalid).
+func feedbackScore(value any) int64 {
+	switch score := value.(type) {
+	case int:
+		return int64(score)
+	case int64:
+		return score
+	case float64:
+		if score == float64(int64(score)) {
+			return int64(score)
+		}
+	case json.Number:
+		if parsed, err := score.Int64(); err == nil {
+			return parsed
+		}
+	}
+	return -1
 }
 
 // bytes32ToTags converts bytes32 tags back to plain strings.
 func (f *FeedbackManager) bytes32ToTags(tag1Bytes, tag2Bytes string) []string {
-
-	// TODO: implementation
-
-	return []string{}
+	tags := []string{}
+	for _, tagBytes := range []string{tag1Bytes, tag2Bytes} {
+		if tag := string(bytes.TrimRight(common.FromHex(tagBytes), "\x00")); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 // GetReputationSummary gets the reputation summary for an agent.
@@ -217,18 +578,59 @@ func (f *FeedbackManager) GetReputationSummary(
 	tag1 string,
 	tag2 string,
 ) ReputationSummary {
+	return f.GetReputationSummaries([]types.AgentID{agentID}, tag1, tag2)[agentID]
+}
 
-	// TODO: implementation
+// GetReputationSummaries gets the reputation summaries for agents (all clients), reading
+// them in batches.
+func (f *FeedbackManager) GetReputationSummaries(
+	agentIDs []types.AgentID,
+	tag1 string,
+	tag2 string,
+) map[types.AgentID]ReputationSummary {
+	reputationRegistry, _ := f.registries()
+	if reputationRegistry == nil {
+		log.Fatal("Reputation registry not available")
+	}
 
-	return ReputationSummary{}
+	calls := make([]ReadCall, len(agentIDs))
+	for idx, agentID := range agentIDs {
+		calls[idx] = ReadCall{reputationRegistry, reputationRegistryBinding.PackGetSummary(
+			utils.ParseAgentID(agentID).TokenID,
+			[]common.Address{},
+			f.stringToBytes32(tag1),
+			f.stringToBytes32(tag2),
+		)}
+	}
+	summaries, errs := batchRead(f.web3Client, calls, reputationRegistryBinding.UnpackGetSummary)
+
+	result := map[types.AgentID]ReputationSummary{}
+	for idx, agentID := range agentIDs {
+		if errs[idx] != nil {
+			log.Fatalf("Failed to get reputation summary of agent %s: %v", agentID, errs[idx])
+		}
+		result[agentID] = ReputationSummary{
+			AverageScore: int64(summaries[idx].AverageScore),
+			Count:        int64(summaries[idx].Count),
+		}
+	}
+	return result
 }
 
 // ...
 
 type Feedback struct {
-	ID    []string
-	Score int64
-	Tags  []string
+	ID            []string
+	FeedbackIndex int64
+	Score         int64
+	Tags          []string
+	IsRevoked     bool
+}
+
+type FeedbackRef struct {
+	AgentID       types.AgentID
+	ClientAddress types.Address
+	FeedbackIndex int64
 }
 
 type FeedbackFile = map[string]any
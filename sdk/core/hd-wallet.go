@@ -0,0 +1,251 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/ryanchristo/agent0-go/sdk/wordlists"
+)
+
+// HD_PATH_SIGNER is the derivation path of the signer with the given index (BIP-44
+// Ethereum path, compatible with common wallets).
+const HD_PATH_SIGNER = "m/44'/60'/0'/0/%d"
+
+// HD_PATH_AGENT_WALLET is the derivation path of the agent wallet with the given index.
+// Agent wallets use a separate account so that they never share keys with signers.
+const HD_PATH_AGENT_WALLET = "m/44'/60'/1'/0/%d"
+
+// bip39Words is the BIP-39 English wordlist and bip39Index maps words to their index.
+var bip39Words = strings.Fields(wordlists.English)
+var bip39Index = func() map[string]int {
+	index := make(map[string]int, len(bip39Words))
+	for idx, word := range bip39Words {
+		index[word] = idx
+	}
+	return index
+}()
+
+// HDWallet derives signers from a single BIP-39 seed using BIP-32 derivation paths.
+// Derivation is deterministic: the same mnemonic, passphrase and index always produce
+// the same key.
+type HDWallet struct {
+	master hdKey
+}
+
+// GenerateMnemonic generates a new BIP-39 English mnemonic with the given entropy size
+// in bits (128 for 12 words up to 256 for 24 words).
+func GenerateMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("invalid entropy size %d (expected 128, 160, 192, 224 or 256)", bits)
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	return mnemonicFromEntropy(entropy), nil
+}
+
+// ValidateMnemonic checks that the mnemonic has a valid length, words and checksum.
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return fmt.Errorf("invalid mnemonic length %d (expected 12, 15, 18, 21 or 24 words)", len(words))
+	}
+
+	// Rebuild the entropy and checksum bits from the word indexes
+	bits := new(big.Int)
+	for _, word := range words {
+		idx, ok := bip39Index[word]
+		if !ok {
+			return fmt.Errorf("invalid mnemonic word %q", word)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(idx)))
+	}
+
+	checksumBits := len(words) / 3
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1))
+	entropy := new(big.Int).Rsh(bits, uint(checksumBits)).FillBytes(make([]byte, checksumBits*4))
+
+	expected := sha256.Sum256(entropy)
+	if uint64(expected[0]>>(8-checksumBits)) != checksum.Uint64() {
+		return errors.New("invalid mnemonic checksum")
+	}
+
+	return nil
+}
+
+// NewHDWallet creates a new HD wallet from a BIP-39 mnemonic and optional passphrase.
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	// BIP-39 seed (the mnemonic is normalized and words are separated by single spaces)
+	normalized := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := []byte("mnemonic" + norm.NFKD.String(passphrase))
+	seed, err := pbkdf2.Key(sha512.New, normalized, salt, 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %w", err)
+	}
+
+	return NewHDWalletFromSeed(seed)
+}
+
+// NewHDWalletFromSeed creates a new HD wallet from a BIP-32 seed (16 to 64 bytes).
+func NewHDWalletFromSeed(seed []byte) (*HDWallet, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid seed length %d (expected 16 to 64 bytes)", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errors.New("invalid seed (derived master key is out of range)")
+	}
+
+	return &HDWallet{master: hdKey{key: key, chainCode: sum[32:]}}, nil
+}
+
+// Derive derives the signer at the given BIP-32 path (e.g. "m/44'/60'/0'/0/0").
+func (w *HDWallet) Derive(path string) (*PrivateKeySigner, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := w.master
+	for _, index := range indexes {
+		if key, err = key.child(index); err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+	}
+
+	privateKey, err := crypto.ToECDSA(key.key.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+	}
+
+	return NewPrivateKeySignerFromECDSA(privateKey), nil
+}
+
+// Signer derives the signer with the given index (see HD_PATH_SIGNER).
+func (w *HDWallet) Signer(index uint32) (*PrivateKeySigner, error) {
+	return w.Derive(fmt.Sprintf(HD_PATH_SIGNER, index))
+}
+
+// AgentWallet derives the agent wallet with the given index (see HD_PATH_AGENT_WALLET).
+func (w *HDWallet) AgentWallet(index uint32) (*PrivateKeySigner, error) {
+	return w.Derive(fmt.Sprintf(HD_PATH_AGENT_WALLET, index))
+}
+
+// mnemonicFromEntropy encodes the entropy as a BIP-39 English mnemonic.
+func mnemonicFromEntropy(entropy []byte) string {
+	checksumBits := len(entropy) / 4
+	hash := sha256.Sum256(entropy)
+
+	// Append the checksum bits to the entropy bits
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, uint(checksumBits))
+	bits.Or(bits, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	count := (len(entropy)*8 + checksumBits) / 11
+	words := make([]string, count)
+	mask := big.NewInt(2047)
+	for idx := count - 1; idx >= 0; idx-- {
+		words[idx] = bip39Words[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// parseDerivationPath parses a BIP-32 path into child indexes (hardened indexes are
+// marked with an apostrophe or "h").
+func parseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+	if len(segments) == 0 || segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q (expected to start with \"m\")", path)
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q (invalid index %q)", path, segment)
+		}
+		if hardened {
+			index += hdHardenedOffset
+		}
+		indexes = append(indexes, uint32(index))
+	}
+
+	return indexes, nil
+}
+
+// child derives the BIP-32 child key with the given index.
+func (k hdKey) child(index uint32) (hdKey, error) {
+	keyBytes := k.key.FillBytes(make([]byte, 32))
+
+	data := make([]byte, 0, 37)
+	if index >= hdHardenedOffset {
+		data = append(data, 0)
+		data = append(data, keyBytes...)
+	} else {
+		privateKey, err := crypto.ToECDSA(keyBytes)
+		if err != nil {
+			return hdKey{}, err
+		}
+		data = append(data, crypto.CompressPubkey(&privateKey.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return hdKey{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	key := tweak.Add(tweak, k.key)
+	key.Mod(key, n)
+	if key.Sign() == 0 {
+		return hdKey{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+
+	return hdKey{key: key, chainCode: sum[32:]}, nil
+}
+
+// ...
+
+const hdHardenedOffset = 0x80000000
+
+type hdKey struct {
+	key       *big.Int
+	chainCode []byte
+}
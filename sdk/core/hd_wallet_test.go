@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestHDWalletDerivesWalletAddresses(t *testing.T) {
+	wallet, err := NewHDWallet(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("NewHDWallet error: %v", err)
+	}
+
+	signer, err := wallet.Signer(0)
+	if err != nil {
+		t.Fatalf("Signer error: %v", err)
+	}
+	if got, want := signer.Address().Hex(), "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"; got != want {
+		t.Errorf("m/44'/60'/0'/0/0 address = %s, want %s", got, want)
+	}
+
+	derived, err := wallet.Derive("m/44h/60h/0h/0/0")
+	if err != nil {
+		t.Fatalf("Derive error: %v", err)
+	}
+	if derived.Address() != signer.Address() {
+		t.Errorf("Derive with h hardened markers = %s, want %s", derived.Address(), signer.Address())
+	}
+
+	agentWallet, err := wallet.AgentWallet(0)
+	if err != nil {
+		t.Fatalf("AgentWallet error: %v", err)
+	}
+	if agentWallet.Address() == signer.Address() {
+		t.Errorf("agent wallet shares the address of the signer")
+	}
+
+	// The passphrase is part of the seed
+	protected, err := NewHDWallet(testMnemonic, "TREZOR")
+	if err != nil {
+		t.Fatalf("NewHDWallet error: %v", err)
+	}
+	if protectedSigner, _ := protected.Signer(0); protectedSigner.Address() == signer.Address() {
+		t.Errorf("passphrase does not change the derived address")
+	}
+}
+
+func TestHDWalletBIP32TestVector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	wallet, err := NewHDWalletFromSeed(seed)
+	if err != nil {
+		t.Fatalf("NewHDWalletFromSeed error: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		key       string
+		chainCode string
+	}{
+		{
+			path:      "m",
+			key:       "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			chainCode: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+		},
+		{
+			path:      "m/0'",
+			key:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+		},
+		{
+			path:      "m/0'/1",
+			key:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+		},
+		{
+			path:      "m/0'/1/2'",
+			key:       "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			chainCode: "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+		},
+		{
+			path:      "m/0'/1/2'/2",
+			key:       "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+			chainCode: "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd",
+		},
+		{
+			path:      "m/0'/1/2'/2/1000000000",
+			key:       "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+			chainCode: "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			indexes, err := parseDerivationPath(tt.path)
+			if err != nil {
+				t.Fatalf("parseDerivationPath error: %v", err)
+			}
+			key := wallet.master
+			for _, index := range indexes {
+				if key, err = key.child(index); err != nil {
+					t.Fatalf("child(%d) error: %v", index, err)
+				}
+			}
+
+			if got := hex.EncodeToString(key.key.FillBytes(make([]byte, 32))); got != tt.key {
+				t.Errorf("key = %s, want %s", got, tt.key)
+			}
+			if got := hex.EncodeToString(key.chainCode); got != tt.chainCode {
+				t.Errorf("chain code = %s, want %s", got, tt.chainCode)
+			}
+
+			signer, err := wallet.Derive(tt.path)
+			if err != nil {
+				t.Fatalf("Derive error: %v", err)
+			}
+			if got := hex.EncodeToString(signer.privateKey.D.FillBytes(make([]byte, 32))); got != tt.key {
+				t.Errorf("Derive(%q) key = %s, want %s", tt.path, got, tt.key)
+			}
+		})
+	}
+}
+
+func TestMnemonicChecksumRoundTrip(t *testing.T) {
+	// BIP-39 test vectors (entropy to mnemonic)
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{entropy: strings.Repeat("00", 16), mnemonic: testMnemonic},
+		{entropy: strings.Repeat("7f", 16), mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{entropy: strings.Repeat("80", 16), mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{entropy: strings.Repeat("ff", 16), mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+		{entropy: strings.Repeat("00", 32), mnemonic: strings.Repeat("abandon ", 23) + "art"},
+	}
+	for _, vector := range vectors {
+		entropy, _ := hex.DecodeString(vector.entropy)
+		if got := mnemonicFromEntropy(entropy); got != vector.mnemonic {
+			t.Errorf("mnemonicFromEntropy(%s) = %q, want %q", vector.entropy, got, vector.mnemonic)
+		}
+		if err := ValidateMnemonic(vector.mnemonic); err != nil {
+			t.Errorf("ValidateMnemonic(%q) error: %v", vector.mnemonic, err)
+		}
+	}
+
+	// Generated mnemonics of every size validate
+	for bits := 128; bits <= 256; bits += 32 {
+		mnemonic, err := GenerateMnemonic(bits)
+		if err != nil {
+			t.Fatalf("GenerateMnemonic(%d) error: %v", bits, err)
+		}
+		if words := len(strings.Fields(mnemonic)); words != bits/32*3 {
+			t.Errorf("GenerateMnemonic(%d) has %d words, want %d", bits, words, bits/32*3)
+		}
+		if err := ValidateMnemonic(mnemonic); err != nil {
+			t.Errorf("ValidateMnemonic(%q) error: %v", mnemonic, err)
+		}
+	}
+
+	invalid := []string{
+		strings.Repeat("abandon ", 12),              // invalid checksum
+		strings.Repeat("zoo ", 12),                  // invalid checksum
+		strings.Repeat("abandon ", 11) + "notaword", // unknown word
+		strings.Repeat("abandon ", 8) + "about",     // invalid length
+		strings.Repeat("abandon ", 23) + "about",    // invalid checksum (24 words)
+	}
+	for _, mnemonic := range invalid {
+		if err := ValidateMnemonic(mnemonic); err == nil {
+			t.Errorf("ValidateMnemonic(%q) error = nil, want an error", mnemonic)
+		}
+	}
+	if _, err := GenerateMnemonic(100); err == nil {
+		t.Errorf("GenerateMnemonic(100) error = nil, want an error")
+	}
+}
@@ -1,17 +1,37 @@
 package core
 
 import (
+	"cmp"
 	"encoding/json"
-
+	"log"
+	"maps"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanchristo/agent0-go/sdk/caip"
 	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
 )
 
+// REPUTATION_SORT_FIELDS are the sort fields that require agent reputation.
+var REPUTATION_SORT_FIELDS = []string{
+	"feedbackCount",
+	"averageScore",
+	"completedValidations",
+	"averageValidationScore",
+}
+
 // AgentIndexer is an agent indexer that primarily uses subgraph queries.
 // The current version does not support local indexing or ML capabilities.
 type AgentIndexer struct {
 	web3Client           *Web3Client
 	subgraphClient       *SubgraphClient
 	subgraphURLOverrides map[types.ChainID]string
+	registrationFiles    *RegistrationFileReader
+	registryOverrides    RegistryOverrides
 }
 
 // NewAgentIndexer creates a new agent indexer.
@@ -24,6 +44,7 @@ func NewAgentIndexer(
 		web3Client:           web3Client,
 		subgraphClient:       subgraphClient,
 		subgraphURLOverrides: subgraphURLOverrides,
+		registrationFiles:    NewRegistrationFileReader(),
 	}
 }
 
@@ -46,39 +67,185 @@ func (i *AgentIndexer) SearchAgents(
 	// default pageSize = 50
 	// default sort = []
 
-	// TODO: implementation
+	if pageSize == 0 {
+		pageSize = utils.DEFAULTS["SEARCH_PAGE_SIZE"]
+	}
 
-	return AgentSearchResult{}
+	// Multi-chain, sorted, deduplicated and reputation filtered searches are resolved in memory
+	if len(params.Chains) > 0 || len(sort) > 0 || len(params.DeduplicateBy) > 0 || params.HasReputationFilters() {
+		return i.searchAgentsAcrossChains(params, sort, pageSize, cursor, 0)
+	}
+
+	if i.subgraphClient == nil {
+		log.Fatal("Subgraph client required for searchAgents")
+	}
+
+	// Parse cursor to skip value
+	skip := int64(0)
+	if cursor != "" {
+		parsedCursor, err := strconv.ParseInt(cursor, 10, 64)
+		if err == nil {
+			skip = parsedCursor
+		}
+	}
+
+	agents, fetched, err := i.subgraphClient.SearchAgents(params, pageSize, skip)
+	if err == nil && params.IncludeReputation {
+		agents, err = i.attachReputation(i.subgraphClient, agents, i.web3Client.ChainID)
+	}
+	if err != nil {
+		log.Fatalf("Failed to search agents: %v", err)
+	}
+
+	// Pages filtered client-side may be short before the last page
+	nextCursor := ""
+	if fetched == pageSize {
+		nextCursor = strconv.FormatInt(skip+pageSize, 10)
+	}
+
+	return AgentSearchResult{
+		Items:      agents,
+		NextCursor: nextCursor,
+	}
 }
 
 // filterAgents filters agents based on the given search criteria.
 func (i *AgentIndexer) filterAgents(agents []types.AgentSummary, params types.SearchParams) []types.AgentSummary {
+	if !params.HasReputationFilters() {
+		return agents
+	}
 
-	// TODO: implementation
+	filtered := make([]types.AgentSummary, 0, len(agents))
+	for _, agent := range agents {
+		if matchesReputationFilters(agent.Reputation, params) {
+			filtered = append(filtered, agent)
+		}
+	}
 
-	return agents
+	return filtered
+}
+
+// attachReputation attaches the reputation to each agent, fetched in bulk from the subgraph.
+// The agent stats are read in one subgraph query per 1000 agents, so no contract reads
+// are batched here (see GetReputationSummaries for on-chain summaries).
+func (i *AgentIndexer) attachReputation(
+	subgraphClient *SubgraphClient,
+	agents []types.AgentSummary,
+	chainID types.ChainID,
+) ([]types.AgentSummary, error) {
+	if len(agents) == 0 {
+		return agents, nil
+	}
+
+	statsIDs := make([]types.AgentID, len(agents))
+	for idx, agent := range agents {
+		statsIDs[idx] = agentStatsID(chainID, agent)
+	}
+
+	stats, err := subgraphClient.GetAgentStats(statsIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range agents {
+		reputation, ok := stats[statsIDs[idx]]
+		if !ok {
+			// Agents without stats have not received feedback or validations yet
+			reputation = types.AgentReputation{ScoreDistribution: []int64{}}
+		}
+		agents[idx].Reputation = &reputation
+	}
+
+	return agents, nil
+}
+
+// fetchAllAgents fetches all agents matching the search criteria from a subgraph.
+// The number of agents is capped at SEARCH_MAX_RESULTS_PER_CHAIN (truncated is set when
+// more agents may match).
+func (i *AgentIndexer) fetchAllAgents(
+	subgraphClient *SubgraphClient,
+	params types.SearchParams,
+) (agents []types.AgentSummary, truncated bool, err error) {
+	batchSize := int64(1000)
+	maxResults := utils.DEFAULTS["SEARCH_MAX_RESULTS_PER_CHAIN"]
+
+	agents = []types.AgentSummary{}
+	for skip := int64(0); skip < maxResults; skip += batchSize {
+		first := min(batchSize, maxResults-skip)
+		batch, fetched, err := subgraphClient.SearchAgents(params, first, skip)
+		if err != nil {
+			return nil, false, err
+		}
+		agents = append(agents, batch...)
+
+		// Batches are filtered client-side, so only a short batch fetched from the subgraph
+		// is the last batch
+		if fetched < first {
+			return agents, false, nil
+		}
+	}
+
+	return agents, true, nil
 }
 
 // getAllConfiguredChains gets all configured chains (chains with subgraph URLs).
 func (i *AgentIndexer) getAllConfiguredChains() []types.ChainID {
+	chains := map[types.ChainID]bool{}
+	for chainID := range DEFAULT_SUBGRAPH_URLS {
+		chains[chainID] = true
+	}
+	for chainID := range i.subgraphURLOverrides {
+		chains[chainID] = true
+	}
+	if i.subgraphClient != nil && i.web3Client != nil {
+		chains[i.web3Client.ChainID] = true
+	}
 
-	// TODO: implementation
-
-	return []types.ChainID{}
+	return slices.Sorted(maps.Keys(chains))
 }
 
 // getSubgraphClientForChain gets the subgraph client for a specific chain.
 func (i *AgentIndexer) getSubgraphClientForChain(chainID types.ChainID) *SubgraphClient {
+	if i.subgraphClient != nil && i.web3Client != nil && chainID == i.web3Client.ChainID {
+		return i.subgraphClient
+	}
 
-	// TODO: implementation
+	url, ok := i.subgraphURLOverrides[chainID]
+	if !ok || url == "" {
+		url, ok = DEFAULT_SUBGRAPH_URLS[chainID]
+	}
+	if ok && url != "" {
+		client := NewSubgraphClient(url)
+		client.registrationFiles = i.registrationFiles
+		return client
+	}
 
 	return nil
 }
 
+// identityRegistry gets the identity registry of a chain ("" when unknown).
+func (i *AgentIndexer) identityRegistry(chainID types.ChainID) types.Address {
+	if registry := i.registryOverrides[chainID]["IDENTITY"]; registry != "" {
+		return registry
+	}
+	return DEFAULT_REGISTRIES[chainID]["IDENTITY"]
+}
+
 // parseMultiChainCursor parses a multi-chain pagination cursor.
 func (i *AgentIndexer) parseMultiChainCursor(cursor string) ParsedMultiChainCursor {
+	if cursor == "" {
+		return ParsedMultiChainCursor{}
+	}
 
-	// TODO: implementation
+	var parsed ParsedMultiChainCursor
+	if err := json.Unmarshal([]byte(cursor), &parsed); err == nil {
+		return parsed
+	}
+
+	// Support plain offsets for backwards compatibility with single-chain cursors
+	if offset, err := strconv.ParseInt(cursor, 10, 64); err == nil {
+		return ParsedMultiChainCursor{GlobalOffset: offset}
+	}
 
 	return ParsedMultiChainCursor{}
 }
@@ -97,28 +264,160 @@ func (i *AgentIndexer) applyCrossChainFilters(
 	agents []types.AgentSummary,
 	params types.SearchParams,
 ) []types.AgentSummary {
-
-	// TODO: implementation
-
 	return i.filterAgents(agents, params)
 }
 
 // dedeuplicateAgentsCrossChain deduplicates agents across chains.
-// This method deduplicates based on agent name and description.
+// Agents are recognized as the same agent using the identity keys of params.DeduplicateBy:
+// the ERC-8004 registrations of the registration file, a shared wallet, ENS name or DID,
+// or the name and description as a fallback for agents without any other identity key.
+// Each group is merged into the first agent of the group, which lists every deployment.
 func (i *AgentIndexer) dedeuplicateAgentsCrossChain(
 	agents []types.AgentSummary,
 	params types.SearchParams,
 ) []types.AgentSummary {
+	if len(params.DeduplicateBy) == 0 || len(agents) < 2 {
+		return agents
+	}
 
-	// TODO: implementation
+	// The subgraphs do not index the registrations, so they are read from the registration
+	// files (agents whose file cannot be read are only deduplicated by their own registration)
+	if slices.Contains(params.DeduplicateBy, types.DEDUP_KEY_REGISTRATIONS) {
+		if err := i.registrationFiles.Fill(agents); err != nil {
+			log.Printf("warning: failed to read registration files for deduplication: %v", err)
+		}
+	}
 
-	return agents
+	// Union-find over agent indexes
+	parents := make([]int, len(agents))
+	for idx := range parents {
+		parents[idx] = idx
+	}
+	var find func(idx int) int
+	find = func(idx int) int {
+		if parents[idx] != idx {
+			parents[idx] = find(parents[idx])
+		}
+		return parents[idx]
+	}
+	union := func(a, b int) {
+		if rootA, rootB := find(a), find(b); rootA != rootB {
+			// Keep the earliest agent as the root so that the merged result keeps its position
+			parents[max(rootA, rootB)] = min(rootA, rootB)
+		}
+	}
+
+	// Link agents sharing an identity key value (first agent seen per value)
+	link := func(key string, idx int, seen map[string]int, allowSameChain bool) {
+		if key == "" {
+			return
+		}
+		if other, ok := seen[key]; ok {
+			if allowSameChain || agents[other].ChainID != agents[idx].ChainID {
+				union(other, idx)
+			}
+			return
+		}
+		seen[key] = idx
+	}
+
+	hasIdentityKey := make([]bool, len(agents))
+	for _, dedupKey := range params.DeduplicateBy {
+		if dedupKey == types.DEDUP_KEY_NAME_DESCRIPTION {
+			continue
+		}
+
+		seen := map[string]int{}
+		for idx, agent := range agents {
+			keys := i.agentIdentityKeys(agent, dedupKey)
+
+			// The own registration of an agent does not identify it across chains
+			ownKeys := 0
+			if dedupKey == types.DEDUP_KEY_REGISTRATIONS {
+				ownKeys = 1
+			}
+			if len(keys) > ownKeys {
+				hasIdentityKey[idx] = true
+			}
+			for _, key := range keys {
+				// Registrations are explicit links, so they may group agents on the same chain
+				link(key, idx, seen, dedupKey == types.DEDUP_KEY_REGISTRATIONS)
+			}
+		}
+	}
+
+	if slices.Contains(params.DeduplicateBy, types.DEDUP_KEY_NAME_DESCRIPTION) {
+		seen := map[string]int{}
+		for idx, agent := range agents {
+			if hasIdentityKey[idx] {
+				continue
+			}
+			for _, key := range i.agentIdentityKeys(agent, types.DEDUP_KEY_NAME_DESCRIPTION) {
+				link(key, idx, seen, false)
+			}
+		}
+	}
+
+	// Merge groups into their root agent, preserving the order of the roots
+	groups := map[int][]int{}
+	for idx := range agents {
+		root := find(idx)
+		groups[root] = append(groups[root], idx)
+	}
+
+	deduplicated := make([]types.AgentSummary, 0, len(groups))
+	for idx, agent := range agents {
+		if find(idx) != idx {
+			continue
+		}
+		if len(groups[idx]) == 1 {
+			deduplicated = append(deduplicated, agent)
+			continue
+		}
+
+		merged := agent
+		merged.Deployments = make([]types.AgentDeployment, 0, len(groups[idx]))
+		registrations := map[string]bool{}
+		reputations := []*types.AgentReputation{}
+		for _, member := range groups[idx] {
+			merged.Deployments = append(merged.Deployments, types.AgentDeployment{
+				ChainID: agents[member].ChainID,
+				AgentID: agents[member].AgentID,
+			})
+			for _, registration := range agents[member].Registrations {
+				registrations[registration] = true
+			}
+			reputations = append(reputations, agents[member].Reputation)
+		}
+		merged.Registrations = slices.Sorted(maps.Keys(registrations))
+		merged.Reputation = combineReputations(reputations)
+		deduplicated = append(deduplicated, merged)
+	}
+
+	return deduplicated
 }
 
 // sortAgentsCrossChain sorts agents across chains.
+// Each sort entry has the format "field:direction" (e.g. "averageScore:desc").
 func (i *AgentIndexer) sortAgentsCrossChain(agents []types.AgentSummary, sort []string) []types.AgentSummary {
+	if len(sort) == 0 {
+		return agents
+	}
 
-	// TODO: implementation
+	slices.SortStableFunc(agents, func(a, b types.AgentSummary) int {
+		for _, entry := range sort {
+			field, direction, _ := strings.Cut(entry, ":")
+
+			result := compareAgentsByField(a, b, field)
+			if strings.EqualFold(direction, string(ORDER_DIRECTION_DESC)) {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	})
 
 	return agents
 }
@@ -133,9 +432,113 @@ func (i *AgentIndexer) searchAgentsAcrossChains(
 ) AgentSearchResult {
 	// default timeout = 30000
 
-	// TODO: implementation
+	if timeout == 0 {
+		timeout = utils.TIMEOUTS["SEARCH_ACROSS_CHAINS"]
+	}
 
-	return AgentSearchResult{}
+	startTime := time.Now()
+
+	chains := params.Chains
+	if len(chains) == 0 {
+		chains = []types.ChainID{i.web3Client.ChainID}
+	}
+
+	needsReputation := params.IncludeReputation || params.HasReputationFilters() || sortsByReputation(sort)
+
+	type chainResult struct {
+		chainID   types.ChainID
+		agents    []types.AgentSummary
+		truncated bool
+		ok        bool
+	}
+
+	// Buffered so that late results after a timeout do not block the goroutines
+	results := make(chan chainResult, len(chains))
+	for _, chainID := range chains {
+		go func(chainID types.ChainID) {
+			subgraphClient := i.getSubgraphClientForChain(chainID)
+			if subgraphClient == nil {
+				results <- chainResult{chainID: chainID}
+				return
+			}
+
+			// A failing subgraph is reported in the failed chains of the results
+			agents, truncated, err := i.fetchAllAgents(subgraphClient, params)
+			if err == nil && needsReputation {
+				agents, err = i.attachReputation(subgraphClient, agents, chainID)
+			}
+			if err != nil {
+				log.Printf("warning: search on chain %d failed: %v", chainID, err)
+				results <- chainResult{chainID: chainID}
+				return
+			}
+
+			results <- chainResult{chainID: chainID, agents: agents, truncated: truncated, ok: true}
+		}(chainID)
+	}
+
+	// Collect results until all chains respond or the timeout is reached
+	successfulChains := []types.ChainID{}
+	truncatedChains := []types.ChainID{}
+	allAgents := []types.AgentSummary{}
+	deadline := time.After(time.Duration(timeout) * time.Millisecond)
+
+collect:
+	for range chains {
+		select {
+		case result := <-results:
+			if result.ok {
+				successfulChains = append(successfulChains, result.chainID)
+				allAgents = append(allAgents, result.agents...)
+				if result.truncated {
+					truncatedChains = append(truncatedChains, result.chainID)
+				}
+			}
+		case <-deadline:
+			break collect
+		}
+	}
+
+	failedChains := []types.ChainID{}
+	for _, chainID := range chains {
+		if !slices.Contains(successfulChains, chainID) {
+			failedChains = append(failedChains, chainID)
+		}
+	}
+
+	// Reputation filters apply to the combined reputation of deduplicated agents
+	allAgents = i.dedeuplicateAgentsCrossChain(allAgents, params)
+	allAgents = i.applyCrossChainFilters(allAgents, params)
+	allAgents = i.sortAgentsCrossChain(allAgents, sort)
+
+	// Apply pagination using the global offset
+	offset := i.parseMultiChainCursor(cursor).GlobalOffset
+	total := int64(len(allAgents))
+	start := min(offset, total)
+	end := min(start+pageSize, total)
+
+	nextCursor := ""
+	if end < total {
+		nextCursor = i.createMultiChainCursor(end)
+	}
+
+	totalMs := time.Since(startTime).Milliseconds()
+
+	return AgentSearchResult{
+		Items:      allAgents[start:end],
+		NextCursor: nextCursor,
+		Meta: types.SearchResultMeta{
+			Chains:           chains,
+			SuccessfulChains: successfulChains,
+			FailedChains:     failedChains,
+			TruncatedChains:  truncatedChains,
+			TotalResults:     total,
+			Timing: types.SearchResultMetaTiming{
+				TotalMs:           totalMs,
+				AveragePerChainMs: totalMs / int64(len(chains)),
+			},
+		},
+	}
 }
 
 // SearchAgentsByReputation searches for agents by reputation.
@@ -194,6 +597,307 @@ func (i *AgentIndexer) searchAgentsByReputationAcrossChains(
 	return AgentSearchResult{}
 }
 
+// MatchesSearchParams checks if an agent matches all of the given search criteria.
+// Reputation criteria only match agents with reputation attached.
+func MatchesSearchParams(agent types.AgentSummary, params types.SearchParams) bool {
+	if len(params.Chains) > 0 && agent.ChainID != 0 && !slices.Contains(params.Chains, agent.ChainID) {
+		return false
+	}
+	if params.Name != "" && !strings.Contains(strings.ToLower(agent.Name), strings.ToLower(params.Name)) {
+		return false
+	}
+	if params.Description != "" &&
+		!strings.Contains(strings.ToLower(agent.Description), strings.ToLower(params.Description)) {
+		return false
+	}
+	if len(params.Owners) > 0 && !containsAnyAddress(agent.Owners, params.Owners) {
+		return false
+	}
+	if len(params.Operators) > 0 && !containsAnyAddress(agent.Operators, params.Operators) {
+		return false
+	}
+	if params.MCP != nil && agent.MCP != *params.MCP {
+		return false
+	}
+	if params.A2A != nil && agent.A2A != *params.A2A {
+		return false
+	}
+	if params.ENS != "" && !strings.EqualFold(agent.ENS, params.ENS) {
+		return false
+	}
+	if params.DID != "" && agent.DID != params.DID {
+		return false
+	}
+	if params.WalletAddress != "" && !agent.WalletAddress.Equal(params.WalletAddress) {
+		return false
+	}
+	if len(params.SupportedTrust) > 0 {
+		trusts := make([]string, len(params.SupportedTrust))
+		for idx, trust := range params.SupportedTrust {
+			trusts[idx] = string(trust)
+		}
+		if !containsAny(agent.SupportedTrusts, trusts) {
+			return false
+		}
+	}
+	if len(params.A2ASkills) > 0 && !containsAny(agent.A2ASkills, params.A2ASkills) {
+		return false
+	}
+	if len(params.MCPTools) > 0 && !containsAny(agent.MCPTools, params.MCPTools) {
+		return false
+	}
+	if len(params.MCPPrompts) > 0 && !containsAny(agent.MCPPrompts, params.MCPPrompts) {
+		return false
+	}
+	if len(params.MCPResources) > 0 && !containsAny(agent.MCPResources, params.MCPResources) {
+		return false
+	}
+	if len(params.OASFSkills) > 0 {
+		expanded := []string{}
+		for _, skill := range params.OASFSkills {
+			expanded = append(expanded, ExpandSkill(skill)...)
+		}
+		if !MatchOASFSlugs(agent.OASFSkills, params.OASFSkills, expanded) {
+			return false
+		}
+	}
+	if len(params.OASFDomains) > 0 {
+		expanded := []string{}
+		for _, domain := range params.OASFDomains {
+			expanded = append(expanded, ExpandDomain(domain)...)
+		}
+		if !MatchOASFSlugs(agent.OASFDomains, params.OASFDomains, expanded) {
+			return false
+		}
+	}
+	if params.Active != nil && agent.Active != *params.Active {
+		return false
+	}
+	if params.X402Support != nil && agent.X402Support != *params.X402Support {
+		return false
+	}
+
+	return !params.HasReputationFilters() || matchesReputationFilters(agent.Reputation, params)
+}
+
+// matchesReputationFilters checks if the reputation matches the reputation search criteria.
+func matchesReputationFilters(reputation *types.AgentReputation, params types.SearchParams) bool {
+	if reputation == nil {
+		return false
+	}
+	if params.MinFeedbackCount != nil && reputation.FeedbackCount < *params.MinFeedbackCount {
+		return false
+	}
+	if params.MinAverageScore != nil && reputation.AverageScore < *params.MinAverageScore {
+		return false
+	}
+	if params.MaxAverageScore != nil && reputation.AverageScore > *params.MaxAverageScore {
+		return false
+	}
+	if params.MinCompletedValidations != nil && reputation.Validations.Completed < *params.MinCompletedValidations {
+		return false
+	}
+	if params.MinAverageValidationScore != nil && reputation.Validations.AverageScore < *params.MinAverageValidationScore {
+		return false
+	}
+	return true
+}
+
+// containsAny checks if any of the values is contained in the list.
+func containsAny(list []string, values []string) bool {
+	for _, value := range values {
+		if slices.Contains(list, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAnyAddress checks if any of the addresses is contained in the list.
+func containsAnyAddress(list []types.Address, addresses []types.Address) bool {
+	for _, address := range addresses {
+		if types.ContainsAddress(list, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// agentStatsID returns the subgraph stats ID (chainID:tokenID) of an agent.
+func agentStatsID(chainID types.ChainID, agent types.AgentSummary) types.AgentID {
+	if strings.Contains(agent.AgentID, ":") {
+		return agent.AgentID
+	}
+	if agent.ChainID != 0 {
+		chainID = agent.ChainID
+	}
+	return utils.FormattedAgentID(chainID, agent.AgentID)
+}
+
+// combineReputations combines the reputations of the deployments of an agent: counts are
+// summed and averages are weighted by their counts (nil when no reputation is attached).
+func combineReputations(reputations []*types.AgentReputation) *types.AgentReputation {
+	var combined *types.AgentReputation
+	scoreSum, validationScoreSum := 0.0, 0.0
+	for _, reputation := range reputations {
+		if reputation == nil {
+			continue
+		}
+		if combined == nil {
+			combined = &types.AgentReputation{ScoreDistribution: []int64{}}
+		}
+		combined.FeedbackCount += reputation.FeedbackCount
+		scoreSum += reputation.AverageScore * float64(reputation.FeedbackCount)
+		for bucket, count := range reputation.ScoreDistribution {
+			if bucket >= len(combined.ScoreDistribution) {
+				combined.ScoreDistribution = append(combined.ScoreDistribution, 0)
+			}
+			combined.ScoreDistribution[bucket] += count
+		}
+		combined.Validations.Total += reputation.Validations.Total
+		combined.Validations.Completed += reputation.Validations.Completed
+		validationScoreSum += reputation.Validations.AverageScore * float64(reputation.Validations.Completed)
+	}
+
+	if combined != nil && combined.FeedbackCount > 0 {
+		combined.AverageScore = scoreSum / float64(combined.FeedbackCount)
+	}
+	if combined != nil && combined.Validations.Completed > 0 {
+		combined.Validations.AverageScore = validationScoreSum / float64(combined.Validations.Completed)
+	}
+	return combined
+}
+
+// registrationKey returns the dedup key of a global agent ID.
+func registrationKey(agentID caip.AgentID) string {
+	return strings.ToLower(agentID.String())
+}
+
+// agentTokenID returns the token ID of an agent (token IDs are uint256 and compare
+// numerically, not as strings). Invalid agent IDs have token ID -1.
+func agentTokenID(agent types.AgentSummary) *big.Int {
+	_, tokenID, _ := strings.Cut(agent.AgentID, ":")
+	if tokenID == "" {
+		tokenID = agent.AgentID
+	}
+	value, ok := new(big.Int).SetString(tokenID, 10)
+	if !ok {
+		return big.NewInt(-1)
+	}
+	return value
+}
+
+// agentIdentityKeys returns the identity key values of an agent for the given dedup key.
+// Registration keys are global agent IDs (CAIP-10 registry and token ID, lowercase) and
+// start with the agent itself ("" when its identity registry is unknown).
+func (i *AgentIndexer) agentIdentityKeys(agent types.AgentSummary, dedupKey types.DedupKey) []string {
+	switch dedupKey {
+	case types.DEDUP_KEY_REGISTRATIONS:
+		chainID := agent.ChainID
+		if prefix, _, ok := strings.Cut(agent.AgentID, ":"); ok && chainID == 0 {
+			chainID, _ = types.ParseChainID(prefix)
+		}
+		tokenID := agentTokenID(agent)
+
+		registrations := []caip.AgentID{}
+		for _, registration := range agent.Registrations {
+			// Format: eip155:chainID:registry:agentID (global agent ID)
+			agentID, err := caip.ParseAgentID(registration)
+			if err != nil || agentID.Registry == "" || agentID.Chain.Namespace != caip.NAMESPACE_EIP155 {
+				continue
+			}
+			registrations = append(registrations, agentID)
+		}
+
+		// The own registry is the identity registry of the chain, or the registry of the
+		// own registration listed in the registration file
+		ownKey := ""
+		if registry := i.identityRegistry(chainID); registry != "" && tokenID.Sign() >= 0 {
+			ownKey = registrationKey(caip.AgentID{
+				Chain:    caip.EIP155ChainID(int64(chainID)),
+				Registry: string(registry),
+				TokenID:  tokenID,
+			})
+		} else {
+			for _, agentID := range registrations {
+				if agentID.Chain.Reference == chainID.String() && agentID.TokenID.Cmp(tokenID) == 0 {
+					ownKey = registrationKey(agentID)
+					break
+				}
+			}
+		}
+
+		keys := []string{ownKey}
+		for _, agentID := range registrations {
+			if key := registrationKey(agentID); !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	case types.DEDUP_KEY_WALLET:
+		if agent.WalletAddress != "" {
+			return []string{agent.WalletAddress.Lower()}
+		}
+	case types.DEDUP_KEY_ENS:
+		if agent.ENS != "" {
+			return []string{strings.ToLower(agent.ENS)}
+		}
+	case types.DEDUP_KEY_DID:
+		if agent.DID != "" {
+			return []string{agent.DID}
+		}
+	case types.DEDUP_KEY_NAME_DESCRIPTION:
+		name := strings.ToLower(strings.TrimSpace(agent.Name))
+		if name != "" {
+			return []string{name + "\n" + strings.ToLower(strings.TrimSpace(agent.Description))}
+		}
+	}
+	return nil
+}
+
+// sortsByReputation checks if any of the sort entries requires agent reputation.
+func sortsByReputation(sort []string) bool {
+	for _, entry := range sort {
+		field, _, _ := strings.Cut(entry, ":")
+		if slices.Contains(REPUTATION_SORT_FIELDS, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareAgentsByField compares two agents by the given sort field.
+// Unknown fields compare as equal.
+func compareAgentsByField(a, b types.AgentSummary, field string) int {
+	reputationA := a.Reputation
+	if reputationA == nil {
+		reputationA = &types.AgentReputation{}
+	}
+	reputationB := b.Reputation
+	if reputationB == nil {
+		reputationB = &types.AgentReputation{}
+	}
+
+	switch field {
+	case "name":
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case "chainId":
+		return cmp.Compare(a.ChainID, b.ChainID)
+	case "agentId":
+		return agentTokenID(a).Cmp(agentTokenID(b))
+	case "feedbackCount":
+		return cmp.Compare(reputationA.FeedbackCount, reputationB.FeedbackCount)
+	case "averageScore":
+		return cmp.Compare(reputationA.AverageScore, reputationB.AverageScore)
+	case "completedValidations":
+		return cmp.Compare(reputationA.Validations.Completed, reputationB.Validations.Completed)
+	case "averageValidationScore":
+		return cmp.Compare(reputationA.Validations.AverageScore, reputationB.Validations.AverageScore)
+	default:
+		return 0
+	}
+}
+
 // ...
 
 type AgentSearchResult struct {
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// newFakeSubgraph serves the agents query of a subgraph, paginated with the first and
+// skip variables (agents are raw subgraph agents, see fakeSubgraphAgent).
+func newFakeSubgraph(t *testing.T, agents []map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := struct {
+			Variables struct {
+				First int `json:"first"`
+				Skip  int `json:"skip"`
+			} `json:"variables"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		start := min(request.Variables.Skip, len(agents))
+		end := min(start+request.Variables.First, len(agents))
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"agents": agents[start:end]},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newFailingSubgraph serves server errors for every query.
+func newFailingSubgraph(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fakeSubgraphAgent returns a raw subgraph agent with a registration file.
+func fakeSubgraphAgent(chainID types.ChainID, tokenID int, registrationFile map[string]any) map[string]any {
+	registrationFile["id"] = fmt.Sprintf("%d:%d", chainID, tokenID)
+	return map[string]any{
+		"id":               fmt.Sprintf("%d:%d", chainID, tokenID),
+		"chainId":          fmt.Sprint(chainID),
+		"agentId":          fmt.Sprint(tokenID),
+		"owner":            "0x0000000000000000000000000000000000000001",
+		"operators":        []string{},
+		"createdAt":        "0",
+		"updatedAt":        "0",
+		"registrationFile": registrationFile,
+	}
+}
+
+func TestSearchAgentsAcrossChainsReportsFailedSubgraphs(t *testing.T) {
+	healthy := newFakeSubgraph(t, []map[string]any{
+		fakeSubgraphAgent(1, 1, map[string]any{"name": "alpha"}),
+		fakeSubgraphAgent(1, 2, map[string]any{"name": "beta"}),
+	})
+	failing := newFailingSubgraph(t)
+
+	indexer := NewAgentIndexer(nil, nil, map[types.ChainID]string{
+		1: healthy.URL,
+		2: failing.URL,
+	})
+	result := indexer.SearchAgents(types.SearchParams{Chains: []types.ChainID{1, 2}}, 10, "", nil)
+
+	if len(result.Items) != 2 {
+		t.Fatalf("got %d agents, want 2", len(result.Items))
+	}
+	if !slices.Equal(result.Meta.SuccessfulChains, []types.ChainID{1}) {
+		t.Errorf("SuccessfulChains = %v, want [1]", result.Meta.SuccessfulChains)
+	}
+	if !slices.Equal(result.Meta.FailedChains, []types.ChainID{2}) {
+		t.Errorf("FailedChains = %v, want [2]", result.Meta.FailedChains)
+	}
+}
+
+func TestSearchAgentsDeduplicatesByRegistrations(t *testing.T) {
+	const registry = "0x8004A818BFB912233c491871b3d84c89A494BD9e"
+	files := newFakeRegistrationFiles(t, map[string]map[string]any{
+		"/agent.json": {
+			"registrations": []map[string]any{
+				{"agentId": 1, "agentRegistry": "eip155:1:" + registry},
+				{"agentId": 7, "agentRegistry": "eip155:2:" + registry},
+			},
+		},
+	})
+
+	mainnetAgent := fakeSubgraphAgent(1, 1, map[string]any{"name": "alpha"})
+	mainnetAgent["agentURI"] = files.URL + "/agent.json"
+	deployment := fakeSubgraphAgent(2, 7, map[string]any{"name": "alpha (chain 2)"})
+	deployment["agentURI"] = files.URL + "/agent.json"
+	unreadable := fakeSubgraphAgent(2, 8, map[string]any{"name": "gamma"})
+	unreadable["agentURI"] = files.URL + "/missing.json"
+
+	indexer := NewAgentIndexer(nil, nil, map[types.ChainID]string{
+		1: newFakeSubgraph(t, []map[string]any{mainnetAgent}).URL,
+		2: newFakeSubgraph(t, []map[string]any{deployment, unreadable}).URL,
+	})
+	indexer.registryOverrides = RegistryOverrides{
+		1: {"IDENTITY": registry},
+		2: {"IDENTITY": registry},
+	}
+	result := indexer.SearchAgents(types.SearchParams{
+		Chains:        []types.ChainID{1, 2},
+		DeduplicateBy: []types.DedupKey{types.DEDUP_KEY_REGISTRATIONS},
+	}, 10, "", nil)
+
+	if len(result.Items) != 2 {
+		t.Fatalf("got %d agents, want 2: %+v", len(result.Items), result.Items)
+	}
+
+	merged := result.Items[slices.IndexFunc(result.Items, func(agent types.AgentSummary) bool {
+		return len(agent.Deployments) > 0
+	})]
+	deployments := []string{}
+	for _, deployment := range merged.Deployments {
+		deployments = append(deployments, fmt.Sprintf("%d:%s", deployment.ChainID, deployment.AgentID))
+	}
+	slices.Sort(deployments)
+	if !slices.Equal(deployments, []string{"1:1", "2:7"}) {
+		t.Errorf("Deployments = %v, want [1:1 2:7]", deployments)
+	}
+	if len(merged.Registrations) != 2 {
+		t.Errorf("Registrations = %v, want both registrations", merged.Registrations)
+	}
+}
+
+func TestSearchAgentsPaginatesPastFilteredBatches(t *testing.T) {
+	// The only match is past the first subgraph batch of 1000 agents, none of which matches
+	agents := make([]map[string]any, 1200)
+	for idx := range agents {
+		agents[idx] = fakeSubgraphAgent(1, idx, map[string]any{"name": fmt.Sprintf("agent %d", idx)})
+	}
+	agents[1100]["registrationFile"].(map[string]any)["name"] = "target"
+
+	indexer := NewAgentIndexer(nil, nil, map[types.ChainID]string{1: newFakeSubgraph(t, agents).URL})
+	result := indexer.SearchAgents(types.SearchParams{Chains: []types.ChainID{1}, Name: "target"}, 10, "", nil)
+
+	if len(result.Items) != 1 || result.Items[0].Name != "target" {
+		t.Fatalf("got %+v, want the target agent", result.Items)
+	}
+	if len(result.Meta.TruncatedChains) != 0 {
+		t.Errorf("TruncatedChains = %v, want none", result.Meta.TruncatedChains)
+	}
+}
@@ -3,13 +3,11 @@ package core
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"log"
 	"maps"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +17,7 @@ import (
 	"github.com/ipfs/kubo/client/rpc"
 	"github.com/multiformats/go-multiaddr"
 
+	"github.com/ryanchristo/agent0-go/sdk/caip"
 	"github.com/ryanchristo/agent0-go/sdk/types"
 	"github.com/ryanchristo/agent0-go/sdk/utils"
 )
@@ -402,6 +401,24 @@ func (c *IPFSClient) AddRegistrationFile(
 	chainID types.ChainID,
 	identityRegistryAddress types.Address,
 ) string {
+	registrations := []AgentRegistration{}
+	if registrationFile.AgentID != "" {
+		registrations = append(registrations, AgentRegistration{
+			AgentID:          registrationFile.AgentID,
+			IdentityRegistry: identityRegistryAddress,
+		})
+	}
+	return c.AddMultiChainRegistrationFile(registrationFile, chainID, registrations)
+}
+
+// AddMultiChainRegistrationFile adds a registration file listing the registrations of the
+// agent on several chains to IPFS and returns the CID. The chain ID is the default chain
+// of the agent wallet.
+func (c *IPFSClient) AddMultiChainRegistrationFile(
+	registrationFile types.RegistrationFile,
+	chainID types.ChainID,
+	agentRegistrations []AgentRegistration,
+) string {
 
 	// Convert the endpoints array data from the internal format { type, value, meta }
 	// to the ERC-8004 format { name, endpoint, version }
@@ -431,28 +448,17 @@ func (c *IPFSClient) AddRegistrationFile(
 		}
 		endpoints = append(endpoints, map[string]any{
 			"name":     "agentWallet",
-			"endpoint": fmt.Sprintf("eip155:%d:%s", walletChainID, registrationFile.WalletAddress),
+			"endpoint": caip.EIP155AccountID(int64(walletChainID), registrationFile.WalletAddress.String()).String(),
 		})
 	}
 
 	// Build registrations array
 	var registrations []map[string]any
-	if registrationFile.AgentID != "" {
-		parts := strings.Split(registrationFile.AgentID, ":")
-		var agentRegistry string
-		if chainID != 0 {
-			agentRegistry = fmt.Sprintf("eip155:%d:%s", chainID, identityRegistryAddress)
-		} else {
-			agentRegistry = fmt.Sprintf("eip155:1:%s", identityRegistryAddress)
-		}
-		agentID, err := strconv.ParseInt(parts[2], 10, 64)
-		if err != nil {
-			log.Fatalf("Failed to parse agent ID: %v", err)
-			return ""
-		}
+	for _, registration := range agentRegistrations {
+		parsedAgentID := utils.ParseAgentID(registration.AgentID)
 		registrations = append(registrations, map[string]any{
-			"agentId":       agentID,
-			"agentRegistry": agentRegistry,
+			"agentId":       parsedAgentID.TokenID,
+			"agentRegistry": caip.EIP155AccountID(int64(parsedAgentID.ChainID), registration.IdentityRegistry.String()).String(),
 		})
 	}
 
@@ -507,6 +513,11 @@ func (c *IPFSClient) Close() {
 
 // ...
 
+type AgentRegistration struct {
+	AgentID          types.AgentID // chainID:tokenID
+	IdentityRegistry types.Address
+}
+
 type IPFSProvider string
 
 const (
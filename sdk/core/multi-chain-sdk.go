@@ -0,0 +1,222 @@
+package core
+
+import (
+	"log"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// MultiChainSDK is an SDK facade over several chains. It holds an SDK instance (web3
+// client, registries and subgraph client) per configured chain, connected on first use,
+// and routes agent operations by the chain prefix of the agent ID.
+type MultiChainSDK struct {
+	cfg    MultiChainSDKConfig
+	chains map[types.ChainID]*SDK
+	mu     sync.Mutex
+}
+
+// NewMultiChainSDK creates a new multi-chain SDK instance (chains are connected lazily).
+func NewMultiChainSDK(cfg MultiChainSDKConfig) *MultiChainSDK {
+	if len(cfg.Chains) == 0 {
+		log.Fatal("No chain configured")
+	}
+	if cfg.DefaultChainID == 0 {
+		if len(cfg.Chains) > 1 {
+			log.Fatal("DefaultChainID is required with several chains")
+		}
+		for chainID := range cfg.Chains {
+			cfg.DefaultChainID = chainID
+		}
+	}
+	if _, ok := cfg.Chains[cfg.DefaultChainID]; !ok {
+		log.Fatalf("Default chain %d is not configured", cfg.DefaultChainID)
+	}
+
+	return &MultiChainSDK{
+		cfg:    cfg,
+		chains: map[types.ChainID]*SDK{},
+	}
+}
+
+// DefaultChainID returns the chain of agent IDs without chain prefix.
+func (m *MultiChainSDK) DefaultChainID() types.ChainID {
+	return m.cfg.DefaultChainID
+}
+
+// ChainIDs returns the configured chains (sorted).
+func (m *MultiChainSDK) ChainIDs() []types.ChainID {
+	return slices.Sorted(maps.Keys(m.cfg.Chains))
+}
+
+// Chain returns the SDK instance of a configured chain, connecting it on first use.
+func (m *MultiChainSDK) Chain(chainID types.ChainID) *SDK {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sdk, ok := m.chains[chainID]; ok {
+		return sdk
+	}
+
+	chainCfg, ok := m.cfg.Chains[chainID]
+	if !ok {
+		log.Fatalf("Chain %d is not configured", chainID)
+	}
+
+	sdk := NewSDK(m.sdkConfig(chainID, chainCfg))
+	if sdk.ChainID() != chainID {
+		log.Fatalf("RPC endpoint of chain %d is on chain %d", chainID, sdk.ChainID())
+	}
+	m.chains[chainID] = sdk
+
+	return sdk
+}
+
+// CreateAgent creates a new agent on the given chain (default chain when 0).
+func (m *MultiChainSDK) CreateAgent(chainID types.ChainID, name, description, imageURL string) *Agent {
+	if chainID == 0 {
+		chainID = m.cfg.DefaultChainID
+	}
+	return m.Chain(chainID).CreateAgent(name, description, imageURL)
+}
+
+// RegisterAgent registers an agent on several chains with a single registration file
+// (see Agent.RegisterOnChains).
+func (m *MultiChainSDK) RegisterAgent(agent *Agent, chainIDs []types.ChainID) MultiChainRegistration {
+	chains := make([]*SDK, len(chainIDs))
+	for idx, chainID := range chainIDs {
+		chains[idx] = m.Chain(chainID)
+	}
+	return agent.RegisterOnChains(chains)
+}
+
+// LoadAgent loads an existing agent from its chain.
+func (m *MultiChainSDK) LoadAgent(agentID types.AgentID) *Agent {
+	sdk, agentID := m.route(agentID)
+	return sdk.LoadAgent(agentID)
+}
+
+// GetAgent gets an agent summary from the subgraph of its chain.
+func (m *MultiChainSDK) GetAgent(agentID types.AgentID) types.AgentSummary {
+	sdk, agentID := m.route(agentID)
+	return sdk.GetAgent(agentID)
+}
+
+// TransferAgent transfers agent ownership on its chain.
+func (m *MultiChainSDK) TransferAgent(agentID types.AgentID, newOwner types.Address) TransferResult {
+	sdk, agentID := m.route(agentID)
+	return sdk.TransferAgent(agentID, newOwner)
+}
+
+// GetAgentOwner gets the current owner address of the agent on its chain.
+func (m *MultiChainSDK) GetAgentOwner(agentID types.AgentID) types.Address {
+	sdk, agentID := m.route(agentID)
+	return sdk.GetAgentOwner(agentID)
+}
+
+// GetAgentOperators gets the current operators of the agent from its chain.
+func (m *MultiChainSDK) GetAgentOperators(agentID types.AgentID) AgentOperators {
+	sdk, agentID := m.route(agentID)
+	return sdk.GetAgentOperators(agentID)
+}
+
+// SignFeedbackAuth signs feedback authorization for an agent on its chain.
+func (m *MultiChainSDK) SignFeedbackAuth(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	indexLimit int64,
+	expiryHours int64,
+) string {
+	sdk, agentID := m.route(agentID)
+	return sdk.SignFeedbackAuth(agentID, clientAddress, indexLimit, expiryHours)
+}
+
+// GiveFeedback submits feedback on the chain of the agent.
+func (m *MultiChainSDK) GiveFeedback(
+	agentID types.AgentID,
+	feedbackFile map[string]any,
+	feedbackAuth string,
+) Feedback {
+	sdk, agentID := m.route(agentID)
+	return sdk.GiveFeedback(agentID, feedbackFile, feedbackAuth)
+}
+
+// GetFeedback reads feedback from the chain of the agent.
+func (m *MultiChainSDK) GetFeedback(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+) Feedback {
+	sdk, agentID := m.route(agentID)
+	return sdk.GetFeedback(agentID, clientAddress, feedbackIndex)
+}
+
+// RevokeFeedback revokes feedback on the chain of the agent.
+func (m *MultiChainSDK) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) string {
+	sdk, agentID := m.route(agentID)
+	return sdk.RevokeFeedback(agentID, feedbackIndex)
+}
+
+// GetReputationSummary gets the reputation summary of an agent from its chain.
+func (m *MultiChainSDK) GetReputationSummary(agentID types.AgentID, tag1 string, tag2 string) ReputationSummary {
+	sdk, agentID := m.route(agentID)
+	return sdk.GetReputationSummary(agentID, tag1, tag2)
+}
+
+// route returns the SDK instance of the chain of an agent and the agent ID with chain
+// prefix (agent IDs without prefix are on the default chain).
+func (m *MultiChainSDK) route(agentID types.AgentID) (*SDK, types.AgentID) {
+	if !strings.Contains(agentID, ":") {
+		agentID = utils.FormattedAgentID(m.cfg.DefaultChainID, agentID)
+	}
+	chainID := utils.ParseAgentID(agentID).ChainID
+	return m.Chain(chainID), agentID
+}
+
+// sdkConfig returns the SDK configuration of a chain: the shared configuration with the
+// endpoints of the chain. Every chain has its own journal directory.
+func (m *MultiChainSDK) sdkConfig(chainID types.ChainID, chainCfg ChainConfig) SDKConfig {
+	cfg := m.cfg.Shared
+	cfg.ChainID = chainID
+	cfg.RPCURL = chainCfg.RPCURL
+	cfg.RPCURLs = chainCfg.RPCURLs
+	cfg.RegistryOverrides = RegistryOverrides{chainID: chainCfg.Registries}
+	cfg.SubgraphURL = chainCfg.SubgraphURL
+
+	// Subgraph URLs of the other chains (multi-chain searches)
+	cfg.SubgraphOverrides = SubgraphOverrides{}
+	for otherChainID, otherCfg := range m.cfg.Chains {
+		if otherCfg.SubgraphURL != "" {
+			cfg.SubgraphOverrides[otherChainID] = otherCfg.SubgraphURL
+		}
+	}
+
+	if cfg.JournalPath != "" {
+		cfg.JournalPath = filepath.Join(cfg.JournalPath, chainID.String())
+	}
+
+	return cfg
+}
+
+// ...
+
+type MultiChainSDKConfig struct {
+	DefaultChainID types.ChainID // chain of agent IDs without chain prefix (required with several chains)
+	Chains         map[types.ChainID]ChainConfig
+
+	// Shared is the configuration shared by all chains (signer, IPFS and transaction
+	// configuration). Its chain, RPC, registry and subgraph fields are ignored.
+	Shared SDKConfig
+}
+
+type ChainConfig struct {
+	RPCURL      types.URI
+	RPCURLs     []types.URI              // failover endpoints (after RPCURL when set)
+	Registries  map[string]types.Address // overrides of the default registries
+	SubgraphURL string                   // overrides the default subgraph URL
+}
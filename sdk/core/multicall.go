@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ryanchristo/agent0-go/sdk/bindings"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// BatchCall executes read-only contract calls in batches: through Multicall3 when it is
+// deployed on the chain, otherwise with JSON-RPC batch requests. The results are in the
+// order of the calls, and a failed call (e.g. a revert) does not fail the other calls.
+func (c *Web3Client) BatchCall(calls []ReadCall) []ReadResult {
+	results, err := c.batchCall(context.Background(), calls)
+	if err != nil {
+		log.Fatalf("Failed to batch contract calls: %v", err)
+	}
+	return results
+}
+
+// batchCall executes read-only contract calls in batches.
+func (c *Web3Client) batchCall(ctx context.Context, calls []ReadCall) ([]ReadResult, error) {
+	results := make([]ReadResult, 0, len(calls))
+	if len(calls) == 0 {
+		return results, nil
+	}
+
+	if c.hasMulticall3(ctx) {
+		for chunk := range slices.Chunk(calls, int(utils.DEFAULTS["MULTICALL_BATCH_SIZE"])) {
+			chunkResults, err := c.multicall(ctx, chunk)
+			if err != nil {
+				// The aggregated call itself failed (e.g. gas cap of the node), retry
+				// the calls of the chunk with a JSON-RPC batch
+				log.Printf("warning: multicall failed, falling back to batch requests: %v", err)
+				chunkResults, err = c.rpcBatchCall(ctx, chunk)
+				if err != nil {
+					return nil, err
+				}
+			}
+			results = append(results, chunkResults...)
+		}
+		return results, nil
+	}
+
+	for chunk := range slices.Chunk(calls, int(utils.DEFAULTS["RPC_BATCH_SIZE"])) {
+		chunkResults, err := c.rpcBatchCall(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunkResults...)
+	}
+	return results, nil
+}
+
+// multicall executes contract calls in a single Multicall3 aggregate3 call (failures are
+// allowed, so that reverts are reported per call).
+func (c *Web3Client) multicall(ctx context.Context, calls []ReadCall) ([]ReadResult, error) {
+	call3s := make([]bindings.Multicall3Call3, len(calls))
+	for idx, call := range calls {
+		call3s[idx] = bindings.Multicall3Call3{
+			Target:       call.Contract.Address(),
+			AllowFailure: true,
+			CallData:     call.Data,
+		}
+	}
+
+	multicall3 := MULTICALL3_ADDRESS.Common()
+	output, err := c.Provider.CallContract(ctx, ethereum.CallMsg{
+		To:   &multicall3,
+		Data: multicall3Binding.PackAggregate3(call3s),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated, err := multicall3Binding.UnpackAggregate3(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack multicall results: %w", err)
+	}
+	if len(aggregated) != len(calls) {
+		return nil, fmt.Errorf("expected %d multicall results, got %d", len(calls), len(aggregated))
+	}
+
+	results := make([]ReadResult, len(calls))
+	for idx, result := range aggregated {
+		if result.Success {
+			results[idx] = ReadResult{Data: result.ReturnData}
+		} else {
+			results[idx] = ReadResult{Err: DecodeRevertData(result.ReturnData)}
+		}
+	}
+	return results, nil
+}
+
+// rpcBatchCall executes contract calls as eth_call requests of a single JSON-RPC batch.
+func (c *Web3Client) rpcBatchCall(ctx context.Context, calls []ReadCall) ([]ReadResult, error) {
+	outputs := make([]hexutil.Bytes, len(calls))
+	batch := make([]rpc.BatchElem, len(calls))
+	for idx, call := range calls {
+		batch[idx] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []any{
+				map[string]any{
+					"to":   call.Contract.Address(),
+					"data": hexutil.Bytes(call.Data),
+				},
+				"latest",
+			},
+			Result: &outputs[idx],
+		}
+	}
+
+	if err := c.Provider.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to send batch request: %w", err)
+	}
+
+	results := make([]ReadResult, len(calls))
+	for idx, elem := range batch {
+		if elem.Error != nil {
+			results[idx] = ReadResult{Err: decodeTransactionError(elem.Error)}
+		} else {
+			results[idx] = ReadResult{Data: outputs[idx]}
+		}
+	}
+	return results, nil
+}
+
+// hasMulticall3 returns whether Multicall3 is deployed on the chain (checked once, unless
+// the check fails).
+func (c *Web3Client) hasMulticall3(ctx context.Context) bool {
+	c.multicallMu.Lock()
+	defer c.multicallMu.Unlock()
+
+	if c.multicallDeployed == nil {
+		code, err := c.Provider.CodeAt(ctx, MULTICALL3_ADDRESS.Common(), nil)
+		if err != nil {
+			return false
+		}
+		deployed := len(code) > 0
+		c.multicallDeployed = &deployed
+	}
+	return *c.multicallDeployed
+}
+
+// batchRead executes read-only contract calls in batches and unpacks their results (used
+// with the typed registry bindings). Failed calls have a zero value and an error.
+func batchRead[T any](c *Web3Client, calls []ReadCall, unpack func([]byte) (T, error)) ([]T, []error) {
+	results, err := c.batchCall(context.Background(), calls)
+	if err != nil {
+		log.Fatalf("Failed to batch contract calls: %v", err)
+	}
+
+	values := make([]T, len(results))
+	errs := make([]error, len(results))
+	for idx, result := range results {
+		if result.Err != nil {
+			errs[idx] = result.Err
+			continue
+		}
+		values[idx], errs[idx] = unpack(result.Data)
+	}
+	return values, errs
+}
+
+// ...
+
+type ReadCall struct {
+	Contract *Contract
+	Data     []byte // packed call data
+}
+
+type ReadResult struct {
+	Data []byte // return data (nil on failure)
+	Err  error  // decoded revert or request error
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// NonceManager allocates transaction nonces locally per signer, so that transactions
+// sent concurrently from the same signer never reuse a nonce. The next nonce of a signer
+// is read from the pending state of the node on first use and after a resync.
+type NonceManager struct {
+	provider *ethclient.Client
+
+	mu       sync.Mutex
+	next     map[common.Address]uint64
+	released map[common.Address][]uint64 // allocated nonces that were never sent (sorted)
+	stale    map[common.Address]bool     // signers to resync on the next allocation
+}
+
+// NewNonceManager creates a new NonceManager instance.
+func NewNonceManager(provider *ethclient.Client) *NonceManager {
+	return &NonceManager{
+		provider: provider,
+		next:     map[common.Address]uint64{},
+		released: map[common.Address][]uint64{},
+		stale:    map[common.Address]bool{},
+	}
+}
+
+// Next allocates the next nonce of the signer. Released nonces are reused first so that
+// no gap is left in the nonce sequence.
+func (m *NonceManager) Next(ctx context.Context, address common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, ok := m.next[address]
+	if !ok || m.stale[address] {
+		pendingNonce, err := m.provider.PendingNonceAt(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+
+		// Nonces allocated locally may not be pending yet, never go back below them
+		nonce = max(nonce, pendingNonce)
+		m.released[address] = slices.DeleteFunc(m.released[address], func(released uint64) bool {
+			return released < pendingNonce
+		})
+		delete(m.stale, address)
+	}
+
+	if released := m.released[address]; len(released) > 0 {
+		m.released[address] = released[1:]
+		return released[0], nil
+	}
+
+	m.next[address] = nonce + 1
+	return nonce, nil
+}
+
+// Release returns a nonce whose transaction was not sent, so that it can be reused.
+func (m *NonceManager) Release(address common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next, ok := m.next[address]
+	if !ok || nonce >= next {
+		// Allocated before a reset, the pending state of the node is authoritative
+		return
+	}
+
+	if nonce == next-1 {
+		m.next[address] = nonce
+		return
+	}
+
+	released := m.released[address]
+	if idx, found := slices.BinarySearch(released, nonce); !found {
+		m.released[address] = slices.Insert(released, idx, nonce)
+	}
+}
+
+// Resync reads the pending nonce of the signer from the node on the next allocation
+// (e.g. after transactions were sent outside of the SDK). Nonces allocated locally
+// are kept, so transactions in flight never share a nonce.
+func (m *NonceManager) Resync(address common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stale[address] = true
+}
+
+// Reset discards all local nonces of the signer (e.g. after pending transactions were
+// dropped by the node).
+func (m *NonceManager) Reset(address common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.next, address)
+	delete(m.released, address)
+	delete(m.stale, address)
+}
+
+// isNonceConflict checks if a transaction was rejected because its nonce is already used.
+// Underpriced replacements are not conflicts: retrying with a resynced nonce would send a
+// new transaction instead of replacing the pending one. Already known transactions are not
+// conflicts either (see isAlreadyKnown).
+func isNonceConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "nonce too low") ||
+		strings.Contains(message, "nonce has already been used")
+}
+
+// isAlreadyKnown checks if a transaction was rejected because the same signed transaction
+// is already in the pool of the node (i.e. it was sent).
+func isAlreadyKnown(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already known") ||
+		strings.Contains(message, "known transaction")
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// newFakeRPC serves JSON-RPC requests with the given handler and returns a client of it.
+func newFakeRPC(t *testing.T, handle func(method string, params []json.RawMessage) (any, error)) *ethclient.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]any{"jsonrpc": "2.0", "id": request.ID}
+		if result, err := handle(request.Method, request.Params); err != nil {
+			response["error"] = map[string]any{"code": -32000, "message": err.Error()}
+		} else {
+			response["result"] = result
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// newFakeNonceRPC serves the pending nonce of every account and counts the reads.
+func newFakeNonceRPC(t *testing.T, pendingNonce *atomic.Uint64, reads *atomic.Int64) *ethclient.Client {
+	return newFakeRPC(t, func(method string, params []json.RawMessage) (any, error) {
+		if method != "eth_getTransactionCount" {
+			return nil, errors.New("unexpected method " + method)
+		}
+		reads.Add(1)
+		return hexutil.Uint64(pendingNonce.Load()), nil
+	})
+}
+
+func TestNonceManagerParallelNextAndRelease(t *testing.T) {
+	var pendingNonce atomic.Uint64
+	var reads atomic.Int64
+	pendingNonce.Store(5)
+	manager := NewNonceManager(newFakeNonceRPC(t, &pendingNonce, &reads))
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	// Concurrent allocations never share a nonce and leave no gap
+	const allocations = 100
+	nonces := make([]uint64, allocations)
+	var wg sync.WaitGroup
+	for idx := range allocations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := manager.Next(context.Background(), address)
+			if err != nil {
+				t.Errorf("Next error: %v", err)
+			}
+			nonces[idx] = nonce
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(nonces)
+	for idx, nonce := range nonces {
+		if nonce != uint64(5+idx) {
+			t.Fatalf("allocated nonces %v, want 5 to %d once each", nonces, 5+allocations-1)
+		}
+	}
+	if reads.Load() != 1 {
+		t.Errorf("read the pending nonce %d times, want once", reads.Load())
+	}
+
+	// Released nonces are reused first (lowest first), the last nonce is given back
+	for _, nonce := range []uint64{30, 10, 104, 20} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.Release(address, nonce)
+		}()
+	}
+	wg.Wait()
+
+	reused := []uint64{}
+	for range 5 {
+		nonce, err := manager.Next(context.Background(), address)
+		if err != nil {
+			t.Fatalf("Next error: %v", err)
+		}
+		reused = append(reused, nonce)
+	}
+	if want := []uint64{10, 20, 30, 104, 105}; !slices.Equal(reused, want) {
+		t.Errorf("allocated %v after the releases, want %v", reused, want)
+	}
+}
+
+func TestNonceManagerResync(t *testing.T) {
+	var pendingNonce atomic.Uint64
+	var reads atomic.Int64
+	manager := NewNonceManager(newFakeNonceRPC(t, &pendingNonce, &reads))
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	next := func() uint64 {
+		t.Helper()
+		nonce, err := manager.Next(context.Background(), address)
+		if err != nil {
+			t.Fatalf("Next error: %v", err)
+		}
+		return nonce
+	}
+
+	for range 3 {
+		next()
+	}
+	manager.Release(address, 1)
+
+	// Local nonces not pending yet are kept when the node is behind
+	manager.Resync(address)
+	if nonce := next(); nonce != 1 {
+		t.Errorf("Next after resync = %d, want the released nonce 1", nonce)
+	}
+	if nonce := next(); nonce != 3 {
+		t.Errorf("Next after resync = %d, want 3", nonce)
+	}
+
+	// Transactions sent outside of the SDK move the nonce forward and drop stale releases
+	manager.Release(address, 3)
+	pendingNonce.Store(10)
+	manager.Resync(address)
+	if nonce := next(); nonce != 10 {
+		t.Errorf("Next after resync = %d, want the pending nonce 10", nonce)
+	}
+	if reads.Load() != 3 {
+		t.Errorf("read the pending nonce %d times, want 3", reads.Load())
+	}
+}
+
+func TestNonceErrors(t *testing.T) {
+	tests := []struct {
+		message  string
+		conflict bool
+		known    bool
+	}{
+		{message: "nonce too low: next nonce 7, tx nonce 6", conflict: true},
+		{message: "Nonce has already been used", conflict: true},
+		{message: "already known", known: true},
+		{message: "known transaction: 0x1234", known: true},
+		{message: "replacement transaction underpriced"},
+		{message: "insufficient funds for gas * price + value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			err := errors.New(tt.message)
+			if isNonceConflict(err) != tt.conflict {
+				t.Errorf("isNonceConflict(%q) = %v, want %v", tt.message, !tt.conflict, tt.conflict)
+			}
+			if isAlreadyKnown(err) != tt.known {
+				t.Errorf("isAlreadyKnown(%q) = %v, want %v", tt.message, !tt.known, tt.known)
+			}
+		})
+	}
+}
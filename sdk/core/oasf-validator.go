@@ -3,6 +3,8 @@ package core
 import (
 	"encoding/json"
 	"log"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/ryanchristo/agent0-go/sdk/taxonomies"
@@ -30,6 +32,100 @@ func ValidateDomain(slug string) bool {
 	return data.Domains[slug] != nil
 }
 
+// ExpandSkill expands a skill slug to all matching slugs in the OASF taxonomy.
+// A category or parent skill expands to itself and all of its descendants (e.g.
+// "natural_language_processing" includes "natural_language_processing/summarization").
+func ExpandSkill(slug string) []string {
+	return expandTaxonomySlug(loadSkillsData().Skills, slug)
+}
+
+// ExpandDomain expands a domain slug to all matching slugs in the OASF taxonomy.
+// A category or parent domain expands to itself and all of its descendants.
+func ExpandDomain(slug string) []string {
+	return expandTaxonomySlug(loadDomainsData().Domains, slug)
+}
+
+// MatchOASFSlugs checks if any of the agent slugs matches any of the searched slugs.
+// The searched slugs must be expanded with ExpandSkill or ExpandDomain; slugs that are
+// not part of the taxonomy still match hierarchically by path.
+func MatchOASFSlugs(agentSlugs []string, searchSlugs []string, expandedSlugs []string) bool {
+	for _, agentSlug := range agentSlugs {
+		if slices.Contains(expandedSlugs, agentSlug) {
+			return true
+		}
+		for _, searchSlug := range searchSlugs {
+			if isSlugDescendant(agentSlug, searchSlug) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expandTaxonomySlug expands a slug to all taxonomy entries at or below it.
+func expandTaxonomySlug(taxonomy map[string]any, slug string) []string {
+	slug = strings.Trim(strings.TrimSpace(slug), "/")
+	if slug == "" {
+		return []string{}
+	}
+
+	// Entries whose path contains the slug as a segment sequence
+	expanded := map[string]bool{}
+	names := map[string]bool{}
+	if !strings.Contains(slug, "/") {
+		names[slug] = true
+	}
+	for key, entry := range taxonomy {
+		if isSlugDescendant(key, slug) {
+			expanded[key] = true
+			names[taxonomyEntryName(key, entry)] = true
+		}
+	}
+
+	// Entries that extend a matched entry (the taxonomy is not strictly path based)
+	for changed := true; changed; {
+		changed = false
+		for key, entry := range taxonomy {
+			if expanded[key] {
+				continue
+			}
+			if fields, ok := entry.(map[string]any); ok {
+				if parent, ok := fields["extends"].(string); ok && names[parent] {
+					expanded[key] = true
+					names[taxonomyEntryName(key, entry)] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	slugs := make([]string, 0, len(expanded))
+	for key := range expanded {
+		slugs = append(slugs, key)
+	}
+	slices.Sort(slugs)
+
+	return slugs
+}
+
+// isSlugDescendant checks if the slug path contains the ancestor path as whole segments.
+func isSlugDescendant(slug, ancestor string) bool {
+	if slug == "" || ancestor == "" {
+		return false
+	}
+	return strings.Contains("/"+slug+"/", "/"+ancestor+"/")
+}
+
+// taxonomyEntryName returns the name of a taxonomy entry (the last slug segment by default).
+func taxonomyEntryName(key string, entry any) string {
+	if fields, ok := entry.(map[string]any); ok {
+		if name, ok := fields["name"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
 var (
 	skillsDataCache  *SkillsData
 	domainsDataCache *DomainsData
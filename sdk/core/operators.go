@@ -0,0 +1,235 @@
+package core
+
+import (
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// Operators of the identity registry (ERC-721 approvals) may update and transfer agents on
+// behalf of their owner: the approved operator of an agent (one per agent, cleared when
+// the agent is transferred) and the operators of all agents of an owner. An SDK whose
+// signer is an operator uses the same Agent and SDK methods as the owner.
+
+// ApproveOperator approves an operator of the agent (replacing the current one) and
+// returns the transaction hash. Only the owner and the operators of all its agents can
+// approve operators.
+func (a *Agent) ApproveOperator(operator types.Address) string {
+	a.requireSigner()
+	if a.registrationFile.AgentID == "" {
+		log.Fatal("Agent must be registered before approving an operator")
+	}
+	if err := operator.Validate(); err != nil {
+		log.Fatalf("Invalid operator address: %v", err)
+	}
+	if operator.IsZero() {
+		log.Fatal("Operator cannot be the zero address (see RevokeOperator)")
+	}
+
+	return a.approve(operator)
+}
+
+// RevokeOperator removes the approved operator of the agent and returns the transaction
+// hash (the operators of all agents of the owner are managed with SDK.SetOperatorForAll).
+func (a *Agent) RevokeOperator() string {
+	a.requireSigner()
+	if a.registrationFile.AgentID == "" {
+		log.Fatal("Agent must be registered before revoking its operator")
+	}
+
+	return a.approve(types.ZERO_ADDRESS)
+}
+
+// ApprovedOperator returns the approved operator of the agent ("" when none).
+func (a *Agent) ApprovedOperator() types.Address {
+	if a.registrationFile.AgentID == "" {
+		return ""
+	}
+	return a.sdk.GetApprovedOperator(a.registrationFile.AgentID)
+}
+
+// Operators returns the current operators of the agent read from chain.
+func (a *Agent) Operators() AgentOperators {
+	if a.registrationFile.AgentID == "" {
+		log.Fatal("Agent must be registered before reading its operators")
+	}
+	return a.sdk.GetAgentOperators(a.registrationFile.AgentID)
+}
+
+// SetOperatorForAll approves (or removes) an operator of all agents of the signer, current
+// and future, and returns the transaction hash.
+func (s *SDK) SetOperatorForAll(operator types.Address, approved bool) string {
+	if s.IsReadOnly() {
+		log.Fatal("Cannot set operator: SDK is in read-only mode.")
+	}
+	call := s.setApprovalForAllCall(operator, approved)
+	txHash, err := s.web3Client.transact(call.contract, TransactionOptions{}, call.data)
+	if err == nil {
+		_, err = s.web3Client.waitForSuccess(txHash)
+	}
+	if err != nil {
+		log.Fatalf("Failed to set operator %s: %v", operator, err)
+	}
+
+	return txHash
+}
+
+// IsOperatorForAll checks if an address is an operator of all agents of an owner.
+func (s *SDK) IsOperatorForAll(owner, operator types.Address) bool {
+	approved, err := callContract(
+		s.GetIdentityRegistry(),
+		identityRegistryBinding.PackIsApprovedForAll(owner.Common(), operator.Common()),
+		identityRegistryBinding.UnpackIsApprovedForAll,
+	)
+	if err != nil {
+		log.Fatalf("Failed to check operator %s of owner %s: %v", operator, owner, err)
+	}
+	return approved
+}
+
+// GetApprovedOperator gets the approved operator of the agent ("" when none).
+func (s *SDK) GetApprovedOperator(agentID types.AgentID) types.Address {
+	approved, err := callContract(
+		s.GetIdentityRegistry(),
+		identityRegistryBinding.PackGetApproved(s.tokenID(agentID)),
+		identityRegistryBinding.UnpackGetApproved,
+	)
+	if err != nil {
+		log.Fatalf("Failed to get approved operator of agent %s: %v", agentID, err)
+	}
+	if approved == (common.Address{}) {
+		return ""
+	}
+	return types.AddressFromCommon(approved)
+}
+
+// CanManageAgent checks if an address is the owner or an operator of the agent.
+func (s *SDK) CanManageAgent(agentID types.AgentID, address types.Address) bool {
+	_, authorized, err := s.agentAuthorization(s.tokenID(agentID), address)
+	if err != nil {
+		log.Fatalf("Failed to check operators of agent %s: %v", agentID, err)
+	}
+	return authorized
+}
+
+// GetAgentOperators gets the current operators of the agent from chain: the approved
+// operator of the agent and the operators of all agents of its owner. The operators of
+// all agents are found from the ApprovalForAll events of the owner (replayed in order,
+// scanned in block ranges from SDKConfig.RegistryDeploymentBlock), and confirmed with
+// isApprovedForAll. When the events cannot be read, the operators indexed by the subgraph
+// are used instead (see GetIndexedAgentOperators).
+func (s *SDK) GetAgentOperators(agentID types.AgentID) AgentOperators {
+	owner := s.GetAgentOwner(agentID)
+	operators := AgentOperators{
+		AgentID:         utils.NormalizeAgentID(agentID),
+		Owner:           owner,
+		Approved:        s.GetApprovedOperator(agentID),
+		OperatorsForAll: []types.Address{},
+	}
+
+	identityRegistry := s.GetIdentityRegistry()
+	approved := map[common.Address]bool{}
+	candidates := []common.Address{}
+	events, err := s.web3Client.getEventsPaged(identityRegistry, "ApprovalForAll", s.deploymentBlock, []any{owner.Common()})
+	if err != nil {
+		if s.subgraphClient == nil {
+			log.Fatalf("Failed to get operators of agent %s: %v", agentID, err)
+		}
+		log.Printf("warning: using indexed operators of agent %s: %v", agentID, err)
+		for _, operator := range s.GetIndexedAgentOperators(agentID) {
+			if !approved[operator.Common()] {
+				candidates = append(candidates, operator.Common())
+				approved[operator.Common()] = true
+			}
+		}
+	}
+
+	for idx := range events {
+		event, err := identityRegistryBinding.UnpackApprovalForAllEvent(&events[idx])
+		if err != nil || events[idx].Removed {
+			continue
+		}
+		if _, ok := approved[event.Operator]; !ok {
+			candidates = append(candidates, event.Operator)
+		}
+		approved[event.Operator] = event.Approved
+	}
+
+	calls := []ReadCall{}
+	for _, operator := range candidates {
+		if approved[operator] {
+			calls = append(calls, ReadCall{identityRegistry, identityRegistryBinding.PackIsApprovedForAll(owner.Common(), operator)})
+		}
+	}
+	confirmed, errs := batchRead(s.web3Client, calls, identityRegistryBinding.UnpackIsApprovedForAll)
+
+	idx := 0
+	for _, operator := range candidates {
+		if !approved[operator] {
+			continue
+		}
+		if errs[idx] != nil {
+			log.Fatalf("Failed to check operator %s of agent %s: %v", operator.Hex(), agentID, errs[idx])
+		}
+		if confirmed[idx] {
+			operators.OperatorsForAll = append(operators.OperatorsForAll, types.AddressFromCommon(operator))
+		}
+		idx++
+	}
+
+	return operators
+}
+
+// GetIndexedAgentOperators gets the operators of the agent indexed by the subgraph (see
+// AgentSummary.Operators), which may lag behind the chain.
+func (s *SDK) GetIndexedAgentOperators(agentID types.AgentID) []types.Address {
+	return s.GetAgent(agentID).Operators
+}
+
+// All returns the approved operator and the operators of all agents of the owner (without
+// duplicates).
+func (o AgentOperators) All() []types.Address {
+	operators := []types.Address{}
+	if o.Approved != "" {
+		operators = append(operators, o.Approved)
+	}
+	for _, operator := range o.OperatorsForAll {
+		if !types.ContainsAddress(operators, operator) {
+			operators = append(operators, operator)
+		}
+	}
+	return operators
+}
+
+// Includes checks if an address is an operator of the agent.
+func (o AgentOperators) Includes(address types.Address) bool {
+	return types.ContainsAddress(o.All(), address)
+}
+
+// Private helper methods
+
+// approve sets the approved operator of the agent (zero address to remove it).
+func (a *Agent) approve(operator types.Address) string {
+	call := a.sdk.approveCall(a.registrationFile.AgentID, operator)
+	txHash, err := a.sdk.web3Client.transact(call.contract, TransactionOptions{}, call.data)
+	if err == nil {
+		_, err = a.sdk.web3Client.waitForSuccess(txHash)
+	}
+	if err != nil {
+		log.Fatalf("Failed to approve operator of agent %s: %v", a.registrationFile.AgentID, err)
+	}
+
+	return txHash
+}
+
+// ...
+
+type AgentOperators struct {
+	AgentID         types.AgentID
+	Owner           types.Address
+	Approved        types.Address   // approved operator of the agent ("" when none)
+	OperatorsForAll []types.Address // operators of all agents of the owner
+}
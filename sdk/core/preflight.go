@@ -0,0 +1,367 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// Errors of the preflight checks (the other problems are reported with the typed errors
+// of the registries, e.g. ErrNotAgentOwner).
+var (
+	ErrNoSigner            = errors.New("no signer configured")
+	ErrInsufficientBalance = errors.New("signer balance is below the estimated transaction cost")
+	ErrFeedbackNotFound    = errors.New("feedback does not exist")
+	ErrFeedbackRevoked     = errors.New("feedback is already revoked")
+)
+
+// Preflight checks the write operations of the SDK before their transactions are sent:
+// the signer must be allowed to send them (owner or approved operator of the agent,
+// valid feedback authorization, existing feedback), every transaction must succeed in a
+// simulation within the fee budget, and the signer balance must cover the estimated cost.
+// Every method returns the list of blocking problems instead of failing on the first one.
+//
+// With SDKConfig.Preflight set, the matching SDK methods run their preflight checks and
+// fail without sending any transaction when a problem is found.
+type Preflight struct {
+	sdk *SDK
+}
+
+// Preflight returns the preflight checks of the SDK.
+func (s *SDK) Preflight() *Preflight {
+	return &Preflight{sdk: s}
+}
+
+// UpdateAgent checks the update of a registered agent: the changed on-chain metadata and
+// the agent URI (the current URI, the checked cost is the same for a new URI).
+func (p *Preflight) UpdateAgent(agent *Agent) PreflightResult {
+	agentID := agent.AgentID()
+	if agentID == "" {
+		log.Fatal("Agent must be registered before updating it")
+	}
+
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if !p.checkAgentPermission(result, agentID, signer) {
+			return nil
+		}
+
+		metadata := agent.collectMetadataForRegistration()
+		calls := []contractCall{}
+		for _, key := range agent.changedMetadataKeys() {
+			calls = append(calls, p.sdk.setMetadataCall(agentID, key, metadata[key]))
+		}
+		return append(calls, p.sdk.setAgentURICall(agentID, agent.AgentURI()))
+	})
+}
+
+// SetAgentURI checks setting the agent URI.
+func (p *Preflight) SetAgentURI(agentID types.AgentID, agentURI types.URI) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if !p.checkAgentPermission(result, agentID, signer) {
+			return nil
+		}
+		return []contractCall{p.sdk.setAgentURICall(agentID, agentURI)}
+	})
+}
+
+// SetMetadata checks setting an on-chain metadata entry of the agent.
+func (p *Preflight) SetMetadata(agentID types.AgentID, key string, value any) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if !p.checkAgentPermission(result, agentID, signer) {
+			return nil
+		}
+		return []contractCall{p.sdk.setMetadataCall(agentID, key, value)}
+	})
+}
+
+// TransferAgent checks transferring the agent ownership to a new owner.
+func (p *Preflight) TransferAgent(agentID types.AgentID, newOwner types.Address) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if err := newOwner.Validate(); err != nil {
+			result.add(err, "invalid new owner address %q", string(newOwner))
+		} else if newOwner.IsZero() {
+			result.add(types.ErrInvalidAddress, "agents cannot be transferred to the zero address")
+		}
+		if !p.checkAgentPermission(result, agentID, signer) || !result.OK() {
+			return nil
+		}
+		return []contractCall{p.sdk.transferCall(agentID, newOwner)}
+	})
+}
+
+// GiveFeedback checks giving feedback, including the feedback authorization: it must be
+// signed for the agent, the signer (client), the chain and the identity registry of the
+// SDK by the agent owner or an approved operator, not be expired and allow the next
+// feedback index.
+func (p *Preflight) GiveFeedback(agentID types.AgentID, feedbackFile map[string]any, feedbackAuth string) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		tokenID := p.sdk.tokenID(agentID)
+
+		owner, signerAuthorized, err := p.sdk.agentAuthorization(tokenID, signer)
+		if err != nil {
+			result.add(err, "cannot read the owner of agent %s: %v", agentID, err)
+			return nil
+		}
+		if signerAuthorized {
+			result.add(ErrSelfFeedbackForbidden, "signer %s is the owner or an operator of agent %s", signer, agentID)
+		}
+
+		auth, err := ParseFeedbackAuth(feedbackAuth)
+		if err != nil {
+			result.add(err, "%v", err)
+			return nil
+		}
+		if auth.AgentID.Cmp(tokenID) != 0 {
+			result.add(ErrFeedbackAuthInvalid, "feedback authorization is for agent %s instead of %s", auth.AgentID.String(), tokenID)
+		}
+		if !auth.ClientAddress.Equal(signer) {
+			result.add(ErrFeedbackAuthInvalid, "feedback authorization is for client %s instead of signer %s", auth.ClientAddress, signer)
+		}
+		if auth.ChainID.Cmp(p.sdk.ChainID().BigInt()) != 0 {
+			result.add(ErrFeedbackAuthInvalid, "feedback authorization is for chain %s instead of %d", auth.ChainID.String(), p.sdk.ChainID())
+		}
+		if identityRegistry := p.sdk.registries["IDENTITY"]; !auth.IdentityRegistry.Equal(identityRegistry) {
+			result.add(ErrFeedbackAuthInvalid, "feedback authorization is for identity registry %s instead of %s", auth.IdentityRegistry, identityRegistry)
+		}
+		if auth.Expiry.Cmp(big.NewInt(time.Now().Unix())) <= 0 {
+			result.add(ErrFeedbackAuthExpired, "feedback authorization expired at %s", time.Unix(auth.Expiry.Int64(), 0).UTC().Format(time.RFC3339))
+		}
+
+		lastIndex := p.sdk.feedbackManager.getLastIndex(p.sdk.GetReputationRegistry(), tokenID, signer)
+		nextIndex := new(big.Int).SetUint64(lastIndex + 1)
+		if auth.IndexLimit.Cmp(nextIndex) < 0 {
+			result.add(ErrIndexLimitExceeded, "feedback authorization allows %s feedback, next feedback index is %s", auth.IndexLimit.String(), nextIndex)
+		}
+
+		if !auth.SignerAddress.Equal(owner) {
+			if _, authorized, err := p.sdk.agentAuthorization(tokenID, auth.SignerAddress); err != nil {
+				result.add(err, "cannot check the feedback authorization signer: %v", err)
+			} else if !authorized {
+				result.add(ErrFeedbackAuthInvalid, "feedback authorization is signed by %s, who is neither the owner nor an operator of agent %s", auth.SignerAddress, agentID)
+			}
+		}
+
+		if !result.OK() {
+			return nil
+		}
+		return []contractCall{p.sdk.giveFeedbackCall(agentID, feedbackFile, feedbackAuth, false)}
+	})
+}
+
+// AppendResponse checks appending a response to feedback (the feedback must exist).
+func (p *Preflight) AppendResponse(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+	response FeedbackResponse,
+) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if err := clientAddress.Validate(); err != nil {
+			result.add(err, "invalid client address %q", string(clientAddress))
+			return nil
+		}
+		if !p.checkFeedbackExists(result, agentID, clientAddress, feedbackIndex) {
+			return nil
+		}
+		return []contractCall{p.sdk.appendResponseCall(agentID, clientAddress, feedbackIndex, response)}
+	})
+}
+
+// RevokeFeedback checks revoking feedback of the signer (the feedback must exist and not
+// be revoked yet).
+func (p *Preflight) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) PreflightResult {
+	return p.run(func(result *PreflightResult, signer types.Address) []contractCall {
+		if !p.checkFeedbackExists(result, agentID, signer, feedbackIndex) {
+			return nil
+		}
+
+		feedback, err := callContract(
+			p.sdk.GetReputationRegistry(),
+			reputationRegistryBinding.PackReadFeedback(p.sdk.tokenID(agentID), signer.Common(), uint64(feedbackIndex)),
+			reputationRegistryBinding.UnpackReadFeedback,
+		)
+		if err != nil {
+			result.add(err, "cannot read feedback %d of agent %s: %v", feedbackIndex, agentID, err)
+			return nil
+		}
+		if feedback.IsRevoked {
+			result.add(ErrFeedbackRevoked, "feedback %d of agent %s is already revoked", feedbackIndex, agentID)
+			return nil
+		}
+		return []contractCall{p.sdk.revokeFeedbackCall(agentID, feedbackIndex)}
+	})
+}
+
+// Private methods
+
+// run runs the checks of a write operation: the signer is required, the operation
+// specific checks return the contract calls to simulate (none when a blocking problem is
+// found), and the total estimated cost of the calls is compared to the signer balance.
+func (p *Preflight) run(check func(result *PreflightResult, signer types.Address) []contractCall) PreflightResult {
+	result := PreflightResult{}
+
+	signer := p.sdk.web3Client.Address()
+	if signer == "" {
+		result.add(ErrNoSigner, "the SDK is in read-only mode")
+		return result
+	}
+
+	calls := check(&result, signer)
+	if len(calls) == 0 {
+		return result
+	}
+
+	result.Cost = new(big.Int)
+	for _, call := range calls {
+		simulation := p.sdk.web3Client.simulate(call.contract, TransactionOptions{}, call.method, call.data)
+		result.Simulations = append(result.Simulations, simulation)
+
+		if !simulation.Success {
+			result.add(simulation.Err, "%s would fail: %v", call.method, simulation.Err)
+			continue
+		}
+		if !simulation.WithinBudget {
+			result.add(ErrFeeBudgetExceeded, "%s max fee %s wei exceeds the fee budget of %s wei", call.method, simulation.MaxFee, p.sdk.web3Client.MaxFeeBudget)
+		}
+		result.Cost.Add(result.Cost, simulation.MaxFee)
+	}
+
+	result.Balance = p.sdk.web3Client.GetBalance(string(signer))
+	if result.Balance.Cmp(result.Cost) < 0 {
+		result.add(ErrInsufficientBalance, "signer %s has %s wei, the transactions may cost up to %s wei", signer, result.Balance, result.Cost)
+	}
+
+	return result
+}
+
+// checkAgentPermission checks that the signer is the owner or an approved operator of the
+// agent (false when a problem is found).
+func (p *Preflight) checkAgentPermission(result *PreflightResult, agentID types.AgentID, signer types.Address) bool {
+	owner, authorized, err := p.sdk.agentAuthorization(p.sdk.tokenID(agentID), signer)
+	if err != nil {
+		result.add(err, "cannot read the owner of agent %s: %v", agentID, err)
+		return false
+	}
+	if !authorized {
+		result.add(ErrNotAgentOwner, "signer %s is neither the owner (%s) nor an operator of agent %s", signer, owner, agentID)
+		return false
+	}
+	return true
+}
+
+// checkFeedbackExists checks that the feedback of a client exists (false when a problem
+// is found). Feedback indexes start at 1.
+func (p *Preflight) checkFeedbackExists(
+	result *PreflightResult,
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+) bool {
+	tokenID := p.sdk.tokenID(agentID)
+	lastIndex := p.sdk.feedbackManager.getLastIndex(p.sdk.GetReputationRegistry(), tokenID, clientAddress)
+	if feedbackIndex < 1 || uint64(feedbackIndex) > lastIndex {
+		result.add(ErrFeedbackNotFound, "client %s has %d feedback for agent %s, index %d does not exist", clientAddress, lastIndex, agentID, feedbackIndex)
+		return false
+	}
+	return true
+}
+
+// agentAuthorization returns the owner of an agent and whether an address may manage it:
+// the owner, an operator approved for all agents of the owner or the approved address of
+// the agent.
+func (s *SDK) agentAuthorization(tokenID *big.Int, address types.Address) (types.Address, bool, error) {
+	identityRegistry := s.GetIdentityRegistry()
+
+	owner, err := callContract(identityRegistry, identityRegistryBinding.PackOwnerOf(tokenID), identityRegistryBinding.UnpackOwnerOf)
+	if err != nil {
+		return "", false, err
+	}
+	if owner == address.Common() {
+		return types.AddressFromCommon(owner), true, nil
+	}
+
+	approvedForAll, err := callContract(
+		identityRegistry,
+		identityRegistryBinding.PackIsApprovedForAll(owner, address.Common()),
+		identityRegistryBinding.UnpackIsApprovedForAll,
+	)
+	if err != nil {
+		return "", false, err
+	}
+	if approvedForAll {
+		return types.AddressFromCommon(owner), true, nil
+	}
+
+	approved, err := callContract(identityRegistry, identityRegistryBinding.PackGetApproved(tokenID), identityRegistryBinding.UnpackGetApproved)
+	if err != nil {
+		return "", false, err
+	}
+	return types.AddressFromCommon(owner), approved == address.Common(), nil
+}
+
+// requirePreflight runs the preflight checks of a write operation when enabled
+// (SDKConfig.Preflight) and fails with the blocking problems.
+func (s *SDK) requirePreflight(operation string, check func(preflight *Preflight) PreflightResult) {
+	if err := s.checkPreflight(operation, check); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// checkPreflight runs a preflight check when enabled and returns its problems as error.
+func (s *SDK) checkPreflight(operation string, check func(preflight *Preflight) PreflightResult) error {
+	if !s.preflight {
+		return nil
+	}
+	if result := check(s.Preflight()); !result.OK() {
+		return fmt.Errorf("preflight of %s failed: %w", operation, result.Err())
+	}
+	return nil
+}
+
+// OK checks that no blocking problem was found.
+func (r PreflightResult) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Err returns the blocking problems joined in a single error (nil when none), matched
+// with errors.Is (e.g. errors.Is(err, ErrInsufficientBalance)).
+func (r PreflightResult) Err() error {
+	errs := make([]error, len(r.Problems))
+	for idx, problem := range r.Problems {
+		errs[idx] = problem
+	}
+	return errors.Join(errs...)
+}
+
+// add adds a blocking problem.
+func (r *PreflightResult) add(err error, format string, args ...any) {
+	r.Problems = append(r.Problems, PreflightProblem{Err: err, Message: fmt.Sprintf(format, args...)})
+}
+
+// Error returns the message of the problem.
+func (p PreflightProblem) Error() string {
+	return p.Message
+}
+
+// Unwrap returns the typed error of the problem.
+func (p PreflightProblem) Unwrap() error {
+	return p.Err
+}
+
+// ...
+
+type PreflightResult struct {
+	Problems    []PreflightProblem // blocking problems (none when the operation can be sent)
+	Simulations []SimulationResult // simulations of the transactions (none when blocked before)
+	Cost        *big.Int           // total max fee of the transactions in wei (nil when not simulated)
+	Balance     *big.Int           // signer balance in wei (nil when not simulated)
+}
+
+type PreflightProblem struct {
+	Err     error // typed error (e.g. ErrNotAgentOwner, ErrInsufficientBalance)
+	Message string
+}
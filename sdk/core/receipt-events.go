@@ -0,0 +1,211 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// DecodeReceipt decodes the registry events of a transaction receipt: Registered,
+// MetadataSet, Transfer, Approval and ApprovalForAll events of the identity registry, and
+// NewFeedback and FeedbackRevoked events of the reputation registry. Logs of other contracts and other
+// events are skipped.
+func (s *SDK) DecodeReceipt(receipt *ethtypes.Receipt) ReceiptEvents {
+	var identityRegistry, reputationRegistry common.Address
+	if contract := s.GetIdentityRegistry(); contract != nil {
+		identityRegistry = contract.Address()
+	}
+	if contract := s.GetReputationRegistry(); contract != nil {
+		reputationRegistry = contract.Address()
+	}
+	return DecodeReceiptEvents(receipt, s.ChainID(), identityRegistry, reputationRegistry)
+}
+
+// DecodeReceiptEvents decodes the registry events of a transaction receipt emitted by
+// the given registries (see SDK.DecodeReceipt).
+func DecodeReceiptEvents(
+	receipt *ethtypes.Receipt,
+	chainID types.ChainID,
+	identityRegistry common.Address,
+	reputationRegistry common.Address,
+) ReceiptEvents {
+	events := ReceiptEvents{TXHash: receipt.TxHash.Hex()}
+	agentID := func(tokenID *big.Int) types.AgentID {
+		return utils.FormattedAgentID(chainID, tokenID.String())
+	}
+
+	for _, l := range receipt.Logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
+
+		switch l.Address {
+		case identityRegistry:
+			if registered, err := identityRegistryBinding.UnpackRegisteredEvent(l); err == nil {
+				events.Registered = append(events.Registered, RegisteredEvent{
+					AgentID:  agentID(registered.AgentId),
+					TokenID:  registered.AgentId,
+					AgentURI: registered.TokenURI,
+					Owner:    types.AddressFromCommon(registered.Owner),
+					LogIndex: l.Index,
+				})
+			} else if metadataSet, err := identityRegistryBinding.UnpackMetadataSetEvent(l); err == nil {
+				events.MetadataSet = append(events.MetadataSet, MetadataSetEvent{
+					AgentID:  agentID(metadataSet.AgentId),
+					Key:      metadataSet.Key,
+					Value:    metadataSet.Value,
+					LogIndex: l.Index,
+				})
+			} else if transfer, err := identityRegistryBinding.UnpackTransferEvent(l); err == nil {
+				events.Transfers = append(events.Transfers, TransferEvent{
+					AgentID:  agentID(transfer.TokenId),
+					TokenID:  transfer.TokenId,
+					From:     types.AddressFromCommon(transfer.From),
+					To:       types.AddressFromCommon(transfer.To),
+					LogIndex: l.Index,
+				})
+			} else if approval, err := identityRegistryBinding.UnpackApprovalEvent(l); err == nil {
+				events.Approvals = append(events.Approvals, ApprovalEvent{
+					AgentID:  agentID(approval.TokenId),
+					TokenID:  approval.TokenId,
+					Owner:    types.AddressFromCommon(approval.Owner),
+					Approved: types.AddressFromCommon(approval.Approved),
+					LogIndex: l.Index,
+				})
+			} else if approvalForAll, err := identityRegistryBinding.UnpackApprovalForAllEvent(l); err == nil {
+				events.ApprovalsForAll = append(events.ApprovalsForAll, ApprovalForAllEvent{
+					Owner:    types.AddressFromCommon(approvalForAll.Owner),
+					Operator: types.AddressFromCommon(approvalForAll.Operator),
+					Approved: approvalForAll.Approved,
+					LogIndex: l.Index,
+				})
+			}
+		case reputationRegistry:
+			if feedback, err := reputationRegistryBinding.UnpackNewFeedbackEvent(l); err == nil {
+				events.NewFeedback = append(events.NewFeedback, NewFeedbackEvent{
+					AgentID:       agentID(feedback.AgentId),
+					ClientAddress: types.AddressFromCommon(feedback.ClientAddress),
+					Score:         int64(feedback.Score),
+					Tags:          bytes32Tags(feedback.Tag1, feedback.Tag2),
+					FeedbackURI:   feedback.FeedbackUri,
+					FeedbackHash:  hexutil.Encode(feedback.FeedbackHash[:]),
+					LogIndex:      l.Index,
+				})
+			} else if revoked, err := reputationRegistryBinding.UnpackFeedbackRevokedEvent(l); err == nil {
+				events.FeedbackRevoked = append(events.FeedbackRevoked, FeedbackRevokedEvent{
+					AgentID:       agentID(revoked.AgentId),
+					ClientAddress: types.AddressFromCommon(revoked.ClientAddress),
+					FeedbackIndex: int64(revoked.FeedbackIndex),
+					LogIndex:      l.Index,
+				})
+			}
+		}
+	}
+
+	return events
+}
+
+// RegisteredAgentID returns the ID of the agent registered by the transaction: from the
+// Registered event, or from the mint (transfer from the zero address) of registries
+// without Registered event.
+func (e ReceiptEvents) RegisteredAgentID() (types.AgentID, bool) {
+	if len(e.Registered) > 0 {
+		return e.Registered[0].AgentID, true
+	}
+	for _, transfer := range e.Transfers {
+		if transfer.IsMint() {
+			return transfer.AgentID, true
+		}
+	}
+	return "", false
+}
+
+// IsMint checks if the transfer is the mint of the agent.
+func (e TransferEvent) IsMint() bool {
+	return e.From.IsZero()
+}
+
+// bytes32Tags converts bytes32 tags to strings (unset tags are skipped).
+func bytes32Tags(tags ...[32]byte) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if text := string(bytes.TrimRight(tag[:], "\x00")); text != "" {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+// ...
+
+type ReceiptEvents struct {
+	TXHash          string
+	Registered      []RegisteredEvent
+	MetadataSet     []MetadataSetEvent
+	Transfers       []TransferEvent
+	Approvals       []ApprovalEvent
+	ApprovalsForAll []ApprovalForAllEvent
+	NewFeedback     []NewFeedbackEvent
+	FeedbackRevoked []FeedbackRevokedEvent
+}
+
+type RegisteredEvent struct {
+	AgentID  types.AgentID
+	TokenID  *big.Int
+	AgentURI types.URI
+	Owner    types.Address
+	LogIndex uint
+}
+
+type MetadataSetEvent struct {
+	AgentID  types.AgentID
+	Key      string
+	Value    []byte
+	LogIndex uint
+}
+
+type TransferEvent struct {
+	AgentID  types.AgentID
+	TokenID  *big.Int
+	From     types.Address // zero address for the mint
+	To       types.Address
+	LogIndex uint
+}
+
+type ApprovalEvent struct {
+	AgentID  types.AgentID
+	TokenID  *big.Int
+	Owner    types.Address
+	Approved types.Address // zero address when the approval is removed
+	LogIndex uint
+}
+
+type ApprovalForAllEvent struct {
+	Owner    types.Address
+	Operator types.Address
+	Approved bool // false when the operator is removed
+	LogIndex uint
+}
+
+type NewFeedbackEvent struct {
+	AgentID       types.AgentID
+	ClientAddress types.Address
+	Score         int64
+	Tags          []string // tags stored on chain (tag1, tag2)
+	FeedbackURI   types.URI
+	FeedbackHash  string
+	LogIndex      uint
+}
+
+type FeedbackRevokedEvent struct {
+	AgentID       types.AgentID
+	ClientAddress types.Address
+	FeedbackIndex int64
+	LogIndex      uint
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+var (
+	testIdentityRegistry   = common.HexToAddress("0x8004A818BFB912233c491871b3d84c89A494BD9e")
+	testReputationRegistry = common.HexToAddress("0x8004B663056A597Dffe9eCcC1965A193B7388713")
+	testOwner              = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	testClient             = common.HexToAddress("0x0000000000000000000000000000000000000002")
+)
+
+// eventLog builds the log of a registry event with the arguments in the order of the
+// event inputs.
+func eventLog(t *testing.T, contractABI string, address common.Address, eventName string, index uint, args ...any) *ethtypes.Log {
+	t.Helper()
+
+	parsed, err := ethabi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := parsed.Events[eventName]
+
+	topics := []common.Hash{event.ID}
+	data := []any{}
+	for idx, input := range event.Inputs {
+		if !input.Indexed {
+			data = append(data, args[idx])
+			continue
+		}
+		topic, err := ethabi.MakeTopics([]any{args[idx]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		topics = append(topics, topic[0][0])
+	}
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ethtypes.Log{Address: address, Topics: topics, Data: packed, Index: index}
+}
+
+func TestDecodeReceiptEvents(t *testing.T) {
+	feedbackHash := [32]byte{0xab}
+	removed := eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "Registered", 9, big.NewInt(8), "ipfs://removed", testOwner)
+	removed.Removed = true
+
+	receipt := &ethtypes.Receipt{
+		TxHash: common.HexToHash("0x01"),
+		Logs: []*ethtypes.Log{
+			eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "Transfer", 0, common.Address{}, testOwner, big.NewInt(7)),
+			eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "Registered", 1, big.NewInt(7), "ipfs://agent", testOwner),
+			eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "MetadataSet", 2, big.NewInt(7), "agentWallet", "agentWallet", []byte{0x01}),
+			eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "Approval", 3, testOwner, testClient, big.NewInt(7)),
+			eventLog(t, IDENTITY_REGISTRY_ABI, testIdentityRegistry, "ApprovalForAll", 4, testOwner, testClient, true),
+			eventLog(t, REPUTATION_REGISTRY_ABI, testReputationRegistry, "NewFeedback", 5, big.NewInt(3), testClient, uint8(90), [32]byte{'f', 'a', 's', 't'}, [32]byte{}, "ipfs://feedback", feedbackHash),
+			eventLog(t, REPUTATION_REGISTRY_ABI, testReputationRegistry, "FeedbackRevoked", 6, big.NewInt(3), testClient, uint64(2)),
+			// Events of other contracts, unknown events and removed logs are skipped
+			eventLog(t, IDENTITY_REGISTRY_ABI, testReputationRegistry, "Registered", 7, big.NewInt(8), "ipfs://other", testOwner),
+			{Address: testIdentityRegistry, Topics: []common.Hash{common.HexToHash("0xff")}, Index: 8},
+			removed,
+			{Address: testIdentityRegistry, Index: 10},
+		},
+	}
+
+	events := DecodeReceiptEvents(receipt, 84532, testIdentityRegistry, testReputationRegistry)
+
+	if events.TXHash != receipt.TxHash.Hex() {
+		t.Errorf("TXHash = %s, want %s", events.TXHash, receipt.TxHash.Hex())
+	}
+	if len(events.Registered) != 1 {
+		t.Fatalf("Registered = %+v, want a single event", events.Registered)
+	}
+	if registered := events.Registered[0]; registered.AgentID != "84532:7" || registered.TokenID.Int64() != 7 ||
+		registered.AgentURI != "ipfs://agent" || !registered.Owner.Equal(types.AddressFromCommon(testOwner)) || registered.LogIndex != 1 {
+		t.Errorf("Registered = %+v, want agent 84532:7 with URI ipfs://agent", registered)
+	}
+	if len(events.MetadataSet) != 1 || events.MetadataSet[0].Key != "agentWallet" || !bytes.Equal(events.MetadataSet[0].Value, []byte{0x01}) {
+		t.Errorf("MetadataSet = %+v, want the agentWallet metadata", events.MetadataSet)
+	}
+	if len(events.Transfers) != 1 || !events.Transfers[0].IsMint() || !events.Transfers[0].To.Equal(types.AddressFromCommon(testOwner)) {
+		t.Errorf("Transfers = %+v, want the mint to the owner", events.Transfers)
+	}
+	if len(events.Approvals) != 1 || !events.Approvals[0].Approved.Equal(types.AddressFromCommon(testClient)) {
+		t.Errorf("Approvals = %+v, want the approval of the client", events.Approvals)
+	}
+	if len(events.ApprovalsForAll) != 1 || !events.ApprovalsForAll[0].Approved || events.ApprovalsForAll[0].LogIndex != 4 {
+		t.Errorf("ApprovalsForAll = %+v, want the operator approval", events.ApprovalsForAll)
+	}
+	if len(events.NewFeedback) != 1 {
+		t.Fatalf("NewFeedback = %+v, want a single event", events.NewFeedback)
+	}
+	if feedback := events.NewFeedback[0]; feedback.AgentID != "84532:3" || feedback.Score != 90 ||
+		!slices.Equal(feedback.Tags, []string{"fast"}) || feedback.FeedbackURI != "ipfs://feedback" ||
+		feedback.FeedbackHash != hexutil.Encode(feedbackHash[:]) {
+		t.Errorf("NewFeedback = %+v, want the feedback of agent 84532:3", feedback)
+	}
+	if len(events.FeedbackRevoked) != 1 || events.FeedbackRevoked[0].FeedbackIndex != 2 {
+		t.Errorf("FeedbackRevoked = %+v, want the revocation of feedback 2", events.FeedbackRevoked)
+	}
+}
+
+func TestReceiptEventsRegisteredAgentID(t *testing.T) {
+	tests := []struct {
+		name   string
+		events ReceiptEvents
+		want   types.AgentID
+		found  bool
+	}{
+		{
+			name: "registered",
+			events: ReceiptEvents{
+				Registered: []RegisteredEvent{{AgentID: "84532:7"}},
+				Transfers:  []TransferEvent{{AgentID: "84532:6", From: types.AddressFromCommon(common.Address{})}},
+			},
+			want:  "84532:7",
+			found: true,
+		},
+		{
+			name: "mint",
+			events: ReceiptEvents{Transfers: []TransferEvent{
+				{AgentID: "84532:5", From: types.AddressFromCommon(testOwner)},
+				{AgentID: "84532:6", From: types.AddressFromCommon(common.Address{})},
+			}},
+			want:  "84532:6",
+			found: true,
+		},
+		{
+			name:   "transfer",
+			events: ReceiptEvents{Transfers: []TransferEvent{{AgentID: "84532:5", From: types.AddressFromCommon(testOwner)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agentID, found := tt.events.RegisteredAgentID()
+			if agentID != tt.want || found != tt.found {
+				t.Errorf("RegisteredAgentID() = %q, %v, want %q, %v", agentID, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestFeedbackIndexAt(t *testing.T) {
+	reputationABI, err := ethabi.JSON(strings.NewReader(REPUTATION_REGISTRY_ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The client gave 4 feedbacks before the block, and 3 in the block
+	var lastIndexBlock string
+	client := &Web3Client{ChainID: 84532}
+	client.Provider = newFakeRPC(t, func(method string, params []json.RawMessage) (any, error) {
+		switch method {
+		case "eth_call":
+			json.Unmarshal(params[1], &lastIndexBlock)
+			output, err := reputationABI.Methods["getLastIndex"].Outputs.Pack(uint64(4))
+			return hexutil.Bytes(output), err
+		case "eth_getLogs":
+			logs := []*ethtypes.Log{}
+			for _, index := range []uint{2, 5, 9} {
+				l := eventLog(t, REPUTATION_REGISTRY_ABI, testReputationRegistry, "NewFeedback", index, big.NewInt(3), testClient, uint8(90), [32]byte{}, [32]byte{}, "", [32]byte{})
+				l.BlockNumber = 100
+				logs = append(logs, l)
+			}
+			return logs, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	})
+	manager := &FeedbackManager{web3Client: client}
+	reputationRegistry := client.GetContract(types.AddressFromCommon(testReputationRegistry), REPUTATION_REGISTRY_ABI)
+
+	feedbackIndex := manager.feedbackIndexAt(reputationRegistry, big.NewInt(3), types.AddressFromCommon(testClient), big.NewInt(100), 5)
+	if feedbackIndex != 6 {
+		t.Errorf("feedbackIndexAt = %d, want 6 (second feedback of the block)", feedbackIndex)
+	}
+	if lastIndexBlock != "0x63" {
+		t.Errorf("read the last index at block %s, want the previous block 0x63", lastIndexBlock)
+	}
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// RegistrationFileReader reads the registration file fields that the subgraphs do not
+// index (the OASF skills and domains and the ERC-8004 registrations) from the agent URIs.
+// Registration files are cached by URI for the lifetime of the reader.
+type RegistrationFileReader struct {
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[types.URI]registrationFileFields
+}
+
+// NewRegistrationFileReader creates a new registration file reader.
+func NewRegistrationFileReader() *RegistrationFileReader {
+	return &RegistrationFileReader{
+		httpClient: &http.Client{
+			// time.Duration receives nanoseconds, so we multiply by milliseconds
+			Timeout: time.Duration(utils.TIMEOUTS["IPFS_GATEWAY"]) * time.Millisecond,
+		},
+		cache: map[types.URI]registrationFileFields{},
+	}
+}
+
+// Fill fills the OASF skills and domains and the registrations of the agents from their
+// registration files, reading up to REGISTRATION_FILE_READS files in parallel. Agents
+// without agent URI or whose registration file cannot be read keep their fields, and the
+// read errors are returned joined.
+func (r *RegistrationFileReader) Fill(agents []types.AgentSummary) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	semaphore := make(chan struct{}, utils.DEFAULTS["REGISTRATION_FILE_READS"])
+	for idx := range agents {
+		if agents[idx].AgentURI == "" {
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(agent *types.AgentSummary) {
+			defer func() { <-semaphore; wg.Done() }()
+
+			fields, err := r.read(agent.AgentURI)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("agent %s: %w", agent.AgentID, err))
+				mu.Unlock()
+				return
+			}
+			agent.OASFSkills = fields.OASFSkills
+			agent.OASFDomains = fields.OASFDomains
+			agent.Registrations = fields.Registrations
+		}(&agents[idx])
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// read reads the fields of the registration file at the given URI (cached on success).
+func (r *RegistrationFileReader) read(uri types.URI) (registrationFileFields, error) {
+	r.mu.Lock()
+	fields, ok := r.cache[uri]
+	r.mu.Unlock()
+	if ok {
+		return fields, nil
+	}
+
+	data, err := r.fetch(string(uri))
+	if err != nil {
+		return registrationFileFields{}, err
+	}
+
+	var rawData map[string]any
+	if err := json.Unmarshal(data, &rawData); err != nil {
+		return registrationFileFields{}, fmt.Errorf("failed to parse registration file %s: %w", uri, err)
+	}
+	fields = parseRegistrationFileFields(rawData)
+
+	r.mu.Lock()
+	r.cache[uri] = fields
+	r.mu.Unlock()
+
+	return fields, nil
+}
+
+// fetch fetches the registration file at the given URI (IPFS through the public gateways,
+// or HTTP).
+func (r *RegistrationFileReader) fetch(uri string) ([]byte, error) {
+	if cid, ok := strings.CutPrefix(uri, "ipfs://"); ok {
+		var errs []error
+		for _, gateway := range utils.IPFS_GATEWAYS {
+			data, err := r.get(gateway + cid)
+			if err == nil {
+				return data, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, fmt.Errorf("failed to retrieve %s from all IPFS gateways: %w", uri, errors.Join(errs...))
+	}
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		return r.get(uri)
+	}
+
+	return nil, fmt.Errorf("unsupported registration file URI: %s", uri)
+}
+
+// get gets the body of an HTTP resource.
+func (r *RegistrationFileReader) get(url string) ([]byte, error) {
+	resp, err := r.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// parseRegistrationFileFields parses the OASF skills and domains of the OASF endpoint and
+// the ERC-8004 registrations of a raw registration file. Endpoints are in the ERC-8004
+// format { name, endpoint, ...meta } or in the internal format { type, value, meta }.
+func parseRegistrationFileFields(rawData map[string]any) registrationFileFields {
+	fields := registrationFileFields{
+		OASFSkills:    []string{},
+		OASFDomains:   []string{},
+		Registrations: transformRegistrations(rawData),
+	}
+
+	rawEndpoints, _ := rawData["endpoints"].([]any)
+	for _, rawEndpoint := range rawEndpoints {
+		endpoint, _ := rawEndpoint.(map[string]any)
+		name, _ := endpoint["name"].(string)
+		if name == "" {
+			name, _ = endpoint["type"].(string)
+		}
+		if !strings.EqualFold(name, string(types.ENDPOINT_TYPE_OASF)) {
+			continue
+		}
+
+		meta := endpoint
+		if nested, ok := endpoint["meta"].(map[string]any); ok {
+			meta = nested
+		}
+		fields.OASFSkills = append(fields.OASFSkills, rawStrings(meta["skills"])...)
+		fields.OASFDomains = append(fields.OASFDomains, rawStrings(meta["domains"])...)
+	}
+
+	return fields
+}
+
+// rawStrings returns the strings of a raw JSON array (other values are skipped).
+func rawStrings(value any) []string {
+	values, _ := value.([]any)
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok && str != "" {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+// hasOASFFilters checks if the search parameters filter on OASF skills or domains.
+func hasOASFFilters(params types.SearchParams) bool {
+	return len(params.OASFSkills) > 0 || len(params.OASFDomains) > 0
+}
+
+// ...
+
+// registrationFileFields are the registration file fields read by the registration file reader.
+type registrationFileFields struct {
+	OASFSkills    []string
+	OASFDomains   []string
+	Registrations []string
+}
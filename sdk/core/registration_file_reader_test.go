@@ -0,0 +1,152 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// newFakeRegistrationFiles serves the given registration files by path, and not found
+// for other paths.
+func newFakeRegistrationFiles(t *testing.T, files map[string]map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(file)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseRegistrationFileFields(t *testing.T) {
+	var rawData map[string]any
+	err := json.Unmarshal([]byte(`{
+		"endpoints": [
+			{"name": "MCP", "endpoint": "https://mcp.example.com"},
+			{"name": "OASF", "endpoint": "https://github.com/agntcy/oasf/", "skills": ["natural_language_processing/summarization"], "domains": ["finance_and_business/finance"]},
+			{"type": "oasf", "value": "https://github.com/agntcy/oasf/", "meta": {"skills": ["images_computer_vision/image_generation", 7]}}
+		],
+		"registrations": [
+			{"agentId": 12, "agentRegistry": "eip155:84532:0x8004A818BFB912233c491871b3d84c89A494BD9e"},
+			{"agentId": "x", "agentRegistry": "eip155:1:0x8004A818BFB912233c491871b3d84c89A494BD9e"}
+		]
+	}`), &rawData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := parseRegistrationFileFields(rawData)
+
+	wantSkills := []string{"natural_language_processing/summarization", "images_computer_vision/image_generation"}
+	if !slices.Equal(fields.OASFSkills, wantSkills) {
+		t.Errorf("OASFSkills = %v, want %v", fields.OASFSkills, wantSkills)
+	}
+	if !slices.Equal(fields.OASFDomains, []string{"finance_and_business/finance"}) {
+		t.Errorf("OASFDomains = %v, want [finance_and_business/finance]", fields.OASFDomains)
+	}
+	wantRegistrations := []string{"eip155:84532:0x8004A818BFB912233c491871b3d84c89A494BD9e:12"}
+	if !slices.Equal(fields.Registrations, wantRegistrations) {
+		t.Errorf("Registrations = %v, want %v", fields.Registrations, wantRegistrations)
+	}
+}
+
+func TestSearchAgentsFiltersOASFFromRegistrationFiles(t *testing.T) {
+	files := newFakeRegistrationFiles(t, map[string]map[string]any{
+		"/summarizer.json": {
+			"endpoints": []map[string]any{{
+				"name":     "OASF",
+				"endpoint": "https://github.com/agntcy/oasf/",
+				"skills":   []string{"natural_language_processing/summarization"},
+			}},
+		},
+		"/painter.json": {
+			"endpoints": []map[string]any{{
+				"name":     "OASF",
+				"endpoint": "https://github.com/agntcy/oasf/",
+				"skills":   []string{"images_computer_vision/image_generation"},
+			}},
+		},
+	})
+
+	agents := []map[string]any{
+		fakeSubgraphAgent(1, 1, map[string]any{"name": "summarizer"}),
+		fakeSubgraphAgent(1, 2, map[string]any{"name": "painter"}),
+		fakeSubgraphAgent(1, 3, map[string]any{"name": "missing file"}),
+		fakeSubgraphAgent(1, 4, map[string]any{"name": "no URI"}),
+	}
+	agents[0]["agentURI"] = files.URL + "/summarizer.json"
+	agents[1]["agentURI"] = files.URL + "/painter.json"
+	agents[2]["agentURI"] = files.URL + "/missing.json"
+	client := NewSubgraphClient(newFakeSubgraph(t, agents).URL)
+
+	tests := []struct {
+		name   string
+		params types.SearchParams
+		want   []string
+	}{
+		{
+			name:   "skill",
+			params: types.SearchParams{OASFSkills: []string{"natural_language_processing/summarization"}},
+			want:   []string{"summarizer"},
+		},
+		{
+			name:   "category",
+			params: types.SearchParams{OASFSkills: []string{"images_computer_vision"}},
+			want:   []string{"painter"},
+		},
+		{
+			name:   "domain",
+			params: types.SearchParams{OASFDomains: []string{"finance_and_business"}},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, _, err := client.SearchAgents(tt.params, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchAgents error: %v", err)
+			}
+
+			names := []string{}
+			for _, agent := range found {
+				names = append(names, agent.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("SearchAgents(%+v) = %v, want %v", tt.params, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistrationFileReaderFillReportsFailures(t *testing.T) {
+	files := newFakeRegistrationFiles(t, map[string]map[string]any{})
+
+	agents := []types.AgentSummary{
+		{AgentID: "1:1", AgentURI: types.URI(files.URL + "/missing.json")},
+		{AgentID: "1:2", AgentURI: "data:application/json,{}"},
+		{AgentID: "1:3"},
+	}
+	err := NewRegistrationFileReader().Fill(agents)
+	if err == nil {
+		t.Fatal("Fill error = nil, want the read failures")
+	}
+	for _, agentID := range []string{"agent 1:1", "agent 1:2"} {
+		if !strings.Contains(err.Error(), agentID) {
+			t.Errorf("Fill error %q does not report %s", err, agentID)
+		}
+	}
+	if strings.Contains(err.Error(), "agent 1:3") {
+		t.Errorf("Fill error %q reports the agent without agent URI", err)
+	}
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	bind "github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ryanchristo/agent0-go/sdk/bindings"
+)
+
+// Typed errors of the registries, matched with errors.Is on the errors returned by
+// transactions and preflight checks (e.g. errors.Is(err, ErrNotAgentOwner)).
+var (
+	ErrNotAgentOwner         = errors.New("caller is not the agent owner or approved operator")
+	ErrAgentNotFound         = errors.New("agent does not exist")
+	ErrFeedbackAuthExpired   = errors.New("feedback authorization expired")
+	ErrIndexLimitExceeded    = errors.New("feedback authorization index limit exceeded")
+	ErrFeedbackAuthInvalid   = errors.New("invalid feedback authorization")
+	ErrSelfFeedbackForbidden = errors.New("agent owners cannot give feedback to their own agent")
+)
+
+// REVERT_ERRORS maps the custom errors of the registry ABIs to typed errors.
+var REVERT_ERRORS = map[string]error{
+	"ERC721InsufficientApproval": ErrNotAgentOwner,
+	"ERC721IncorrectOwner":       ErrNotAgentOwner,
+	"ERC721NonexistentToken":     ErrAgentNotFound,
+}
+
+// REVERT_REASONS maps the revert reasons (lowercase substrings of Error(string)) of the
+// registries to typed errors. The first matching reason wins.
+var REVERT_REASONS = []struct {
+	Reason string
+	Err    error
+}{
+	{"not authorized", ErrNotAgentOwner},
+	{"not owner", ErrNotAgentOwner},
+	{"not agent owner", ErrNotAgentOwner},
+	{"nonexistent", ErrAgentNotFound},
+	{"does not exist", ErrAgentNotFound},
+	{"indexlimit", ErrIndexLimitExceeded},
+	{"index limit", ErrIndexLimitExceeded},
+	{"self-feedback", ErrSelfFeedbackForbidden},
+	{"self feedback", ErrSelfFeedbackForbidden},
+	{"wrong registry", ErrFeedbackAuthInvalid},
+}
+
+// RevertError is a decoded contract revert. It unwraps to the matching typed error
+// (e.g. ErrNotAgentOwner) when the revert is known.
+type RevertError struct {
+	// Name is the name of the error: "Error" for revert reasons, "Panic" for panics,
+	// otherwise the name of the custom error ("" when the revert data is unknown).
+	Name string
+
+	// Reason is the revert reason (or panic reason).
+	Reason string
+
+	// Args are the arguments of the custom error.
+	Args []any
+
+	// Data is the raw revert data.
+	Data []byte
+
+	// Err is the matching typed error (nil when unknown).
+	Err error
+}
+
+// Error returns the revert message.
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case e.Name != "":
+		args := make([]string, len(e.Args))
+		for idx, arg := range e.Args {
+			args[idx] = fmt.Sprint(arg)
+		}
+		return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+	case len(e.Data) > 0:
+		return "execution reverted: " + hexutil.Encode(e.Data)
+	default:
+		return "execution reverted"
+	}
+}
+
+// Unwrap returns the matching typed error.
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeRevertData decodes the revert data of a contract call: Error(string),
+// Panic(uint256) and the custom errors of the registry ABIs.
+func DecodeRevertData(data []byte) *RevertError {
+	revertErr := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+
+	// Error(string) and Panic(uint256)
+	if reason, err := ethabi.UnpackRevert(data); err == nil {
+		revertErr.Name = "Error"
+		if bytes.Equal(data[:4], crypto.Keccak256([]byte("Panic(uint256)"))[:4]) {
+			revertErr.Name = "Panic"
+		}
+		revertErr.Reason = reason
+		revertErr.Err = matchRevertReason(reason)
+		return revertErr
+	}
+
+	// Custom errors
+	for _, registryABI := range registryABIs() {
+		for _, abiError := range registryABI.Errors {
+			if !bytes.Equal(data[:4], abiError.ID[:4]) {
+				continue
+			}
+			revertErr.Name = abiError.Name
+			if args, err := abiError.Inputs.Unpack(data[4:]); err == nil {
+				revertErr.Args = args
+			}
+			revertErr.Err = REVERT_ERRORS[abiError.Name]
+			return revertErr
+		}
+	}
+
+	return revertErr
+}
+
+// ReceiptError returns the decoded revert of a mined transaction that failed (receipt
+// status 0), replaying it with eth_call in its block (nil for successful transactions).
+// The revert is unknown when the replay does not revert (e.g. out of gas).
+func (c *Web3Client) ReceiptError(receipt *ethtypes.Receipt) error {
+	if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+		return nil
+	}
+
+	ctx := context.Background()
+	revertErr := error(&RevertError{})
+	if tx, _, err := c.Provider.TransactionByHash(ctx, receipt.TxHash); err == nil {
+		if from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+			msg := ethereum.CallMsg{From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data()}
+			if _, err := c.Provider.CallContract(ctx, msg, receipt.BlockNumber); err != nil {
+				if decoded := decodeTransactionError(err); errors.As(decoded, new(*RevertError)) {
+					revertErr = decoded
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("transaction %s reverted: %w", receipt.TxHash.Hex(), revertErr)
+}
+
+// decodeTransactionError decodes the revert of a failed call, gas estimation or
+// transaction. Errors that are not reverts are returned unchanged.
+func decodeTransactionError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Nodes return the revert data as error data
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if encoded, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(encoded); decodeErr == nil && len(data) > 0 {
+				return DecodeRevertData(data)
+			}
+		}
+	}
+
+	// Some nodes only return the revert reason in the message
+	message := err.Error()
+	if idx := strings.Index(message, "execution reverted"); idx >= 0 {
+		reason := strings.TrimPrefix(strings.TrimPrefix(message[idx:], "execution reverted"), ":")
+		reason = strings.TrimSpace(reason)
+		if reason == "" {
+			return &RevertError{}
+		}
+		return &RevertError{Name: "Error", Reason: reason, Err: matchRevertReason(reason)}
+	}
+
+	return err
+}
+
+// matchRevertReason returns the typed error matching a revert reason (nil if none).
+func matchRevertReason(reason string) error {
+	if reason == "" {
+		return nil
+	}
+	reason = strings.ToLower(reason)
+	for _, known := range REVERT_REASONS {
+		if strings.Contains(reason, known.Reason) {
+			return known.Err
+		}
+	}
+	return nil
+}
+
+// registryABIs returns the parsed registry ABIs (parsed once by the bindings).
+func registryABIs() []*ethabi.ABI {
+	parsed := []*ethabi.ABI{}
+	for _, metadata := range []*bind.MetaData{
+		&bindings.IdentityRegistryMetaData,
+		&bindings.ReputationRegistryMetaData,
+		&bindings.ValidationRegistryMetaData,
+	} {
+		if contractABI, err := metadata.ParseABI(); err == nil {
+			parsed = append(parsed, contractABI)
+		}
+	}
+	return parsed
+}
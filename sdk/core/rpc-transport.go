@@ -0,0 +1,403 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// RPC_WRITE_METHODS are the JSON-RPC methods that are not retried after the request may
+// have reached a node (other methods are idempotent reads).
+var RPC_WRITE_METHODS = []string{
+	"eth_sendRawTransaction",
+	"eth_sendTransaction",
+	"eth_signTransaction",
+	"eth_sign",
+}
+
+// RPC_TRANSIENT_ERROR_CODES are the JSON-RPC error codes of idempotent requests that are
+// retried (rate limits returned in HTTP 200 responses).
+var RPC_TRANSIENT_ERROR_CODES = []int64{
+	-32005, // limit exceeded
+	429,
+}
+
+// RPC_TRANSIENT_ERROR_MESSAGES are the JSON-RPC error messages (lowercase substrings) of
+// idempotent requests that are retried (rate limits and nodes lagging behind).
+var RPC_TRANSIENT_ERROR_MESSAGES = []string{
+	"rate limit",
+	"too many requests",
+	"header not found",
+	"request timed out",
+}
+
+// FailoverTransport is an HTTP transport for JSON-RPC clients that spreads requests over
+// several endpoints of the same chain. Requests go to the first available endpoint (in
+// configuration order), and are retried with exponential backoff on transient errors
+// (network errors, rate limits and server errors), failing over to the next endpoint.
+// Idempotent requests are also retried on transient JSON-RPC errors of HTTP 200 responses
+// (e.g. rate limits or "header not found" of public RPCs).
+//
+// Endpoints failing repeatedly are skipped for a cooldown period (circuit breaker), and
+// are tried again once it is over. Write requests are only failed over when they could
+// not reach the node (connection errors), so that transactions are never sent twice.
+type FailoverTransport struct {
+	endpoints []*rpcEndpoint
+	policy    RetryPolicy
+	base      http.RoundTripper
+	mu        sync.Mutex
+}
+
+// NewFailoverTransport creates a new failover transport for the given HTTP(S) endpoints.
+func NewFailoverTransport(rpcURLs []string, policy RetryPolicy) (*FailoverTransport, error) {
+	if len(rpcURLs) == 0 {
+		return nil, errors.New("no RPC endpoint")
+	}
+
+	endpoints := make([]*rpcEndpoint, len(rpcURLs))
+	for idx, rpcURL := range rpcURLs {
+		parsed, err := url.Parse(rpcURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC URL %q: %w", rpcURL, err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported RPC URL %q: failover requires HTTP(S) endpoints", rpcURL)
+		}
+		endpoints[idx] = &rpcEndpoint{url: parsed}
+	}
+
+	return &FailoverTransport{
+		endpoints: endpoints,
+		policy:    withRetryDefaults(policy),
+		base:      http.DefaultTransport,
+	}, nil
+}
+
+// RoundTrip sends a JSON-RPC request to the first available endpoint, with retries and
+// failover on transient errors.
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	idempotent := isIdempotentRPCRequest(body)
+
+	for attempt := int64(0); ; attempt++ {
+		endpoint := t.pick()
+
+		endpointURL := *endpoint.url
+		endpointReq := req.Clone(req.Context())
+		endpointReq.URL = &endpointURL
+		endpointReq.Host = ""
+		endpointReq.Body = io.NopCloser(bytes.NewReader(body))
+		endpointReq.ContentLength = int64(len(body))
+
+		resp, err := t.base.RoundTrip(endpointReq)
+		transient := err != nil || isTransientStatus(resp.StatusCode)
+		if !transient && idempotent && resp.StatusCode == http.StatusOK {
+			transient, err = hasTransientRPCError(resp)
+			if err != nil {
+				resp = nil
+			}
+		}
+		if !transient {
+			t.succeeded(endpoint)
+			return resp, nil
+		}
+		t.failed(endpoint)
+
+		// Writes are only sent again when they did not reach the node
+		retryable := idempotent || isDialError(err)
+		if !retryable || attempt >= t.policy.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff(attempt)):
+		}
+	}
+}
+
+// CheckHealth checks every endpoint (eth_chainId) and updates their circuit breakers.
+func (t *FailoverTransport) CheckHealth(ctx context.Context) []EndpointHealth {
+	health := make([]EndpointHealth, len(t.endpoints))
+
+	var wg sync.WaitGroup
+	for idx, endpoint := range t.endpoints {
+		wg.Go(func() {
+			start := time.Now()
+			chainID, err := endpoint.chainID(ctx, t.base)
+			if err != nil {
+				t.failed(endpoint)
+			} else {
+				t.succeeded(endpoint)
+			}
+
+			health[idx] = EndpointHealth{
+				URL:     endpoint.String(),
+				Healthy: err == nil,
+				ChainID: chainID,
+				Latency: time.Since(start).Milliseconds(),
+				Err:     err,
+			}
+		})
+	}
+	wg.Wait()
+
+	return health
+}
+
+// Disable removes an endpoint from rotation (e.g. an endpoint of another chain).
+func (t *FailoverTransport) Disable(endpointURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, endpoint := range t.endpoints {
+		if endpoint.String() == endpointURL {
+			endpoint.disabled = true
+		}
+	}
+}
+
+// pick returns the first endpoint whose circuit is closed, or the endpoint whose
+// cooldown ends first when all circuits are open.
+func (t *FailoverTransport) pick() *rpcEndpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var next *rpcEndpoint
+	for _, endpoint := range t.endpoints {
+		if endpoint.disabled {
+			continue
+		}
+		if !endpoint.openUntil.After(now) {
+			return endpoint
+		}
+		if next == nil || endpoint.openUntil.Before(next.openUntil) {
+			next = endpoint
+		}
+	}
+	if next == nil {
+		return t.endpoints[0] // all disabled, keep using the primary endpoint
+	}
+	return next
+}
+
+// succeeded closes the circuit of an endpoint.
+func (t *FailoverTransport) succeeded(endpoint *rpcEndpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	endpoint.failures = 0
+	endpoint.openUntil = time.Time{}
+}
+
+// failed records a failure of an endpoint and opens its circuit after too many
+// consecutive failures.
+func (t *FailoverTransport) failed(endpoint *rpcEndpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	endpoint.failures++
+	if endpoint.failures >= t.policy.FailureThreshold {
+		endpoint.openUntil = time.Now().Add(time.Duration(t.policy.Cooldown) * time.Millisecond)
+	}
+}
+
+// backoff returns the delay before a retry: exponential with full jitter.
+func (t *FailoverTransport) backoff(attempt int64) time.Duration {
+	delay := t.policy.InitialBackoff << min(attempt, 16)
+	delay = min(delay, t.policy.MaxBackoff)
+	return time.Duration(rand.Int64N(delay)+1) * time.Millisecond
+}
+
+// chainID requests the chain ID of the endpoint.
+func (e *rpcEndpoint) chainID(ctx context.Context, transport http.RoundTripper) (types.ChainID, error) {
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url.String(), strings.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	result := struct {
+		Result hexutil.Big `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("invalid response: %w", err)
+	}
+	if result.Error != nil {
+		return 0, errors.New(result.Error.Message)
+	}
+
+	return types.ChainID(result.Result.ToInt().Int64()), nil
+}
+
+// String returns the URL of the endpoint without credentials.
+func (e *rpcEndpoint) String() string {
+	return e.url.Redacted()
+}
+
+// withRetryDefaults sets the default values of the unset retry policy options.
+func withRetryDefaults(policy RetryPolicy) RetryPolicy {
+	if policy.MaxRetries == 0 {
+		policy.MaxRetries = utils.DEFAULTS["RPC_MAX_RETRIES"]
+	}
+	if policy.InitialBackoff == 0 {
+		policy.InitialBackoff = utils.TIMEOUTS["RPC_RETRY_BACKOFF"]
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = utils.TIMEOUTS["RPC_RETRY_MAX_BACKOFF"]
+	}
+	if policy.FailureThreshold == 0 {
+		policy.FailureThreshold = utils.DEFAULTS["RPC_CIRCUIT_FAILURE_THRESHOLD"]
+	}
+	if policy.Cooldown == 0 {
+		policy.Cooldown = utils.TIMEOUTS["RPC_CIRCUIT_COOLDOWN"]
+	}
+	return policy
+}
+
+// isIdempotentRPCRequest returns whether a JSON-RPC request (or batch) only calls
+// idempotent methods.
+func isIdempotentRPCRequest(body []byte) bool {
+	messages := []struct {
+		Method string `json:"method"`
+	}{}
+	if err := json.Unmarshal(body, &messages); err != nil {
+		message := struct {
+			Method string `json:"method"`
+		}{}
+		if err := json.Unmarshal(body, &message); err != nil {
+			return false
+		}
+		messages = append(messages, message)
+	}
+
+	for _, message := range messages {
+		if slices.Contains(RPC_WRITE_METHODS, message.Method) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTransientStatus returns whether an HTTP status is worth retrying (rate limits and
+// server errors).
+func isTransientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500
+}
+
+// hasTransientRPCError returns whether a JSON-RPC response (or batch) contains a transient
+// error (see RPC_TRANSIENT_ERROR_CODES). The body is buffered so it can still be read,
+// and failing to read it is transient.
+func hasTransientRPCError(resp *http.Response) (bool, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return true, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	type rpcMessage struct {
+		Error *struct {
+			Code    int64  `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	messages := []rpcMessage{}
+	if err := json.Unmarshal(body, &messages); err != nil {
+		message := rpcMessage{}
+		if err := json.Unmarshal(body, &message); err != nil {
+			return false, nil // left to the JSON-RPC client
+		}
+		messages = append(messages, message)
+	}
+
+	for _, message := range messages {
+		if message.Error == nil {
+			continue
+		}
+		if slices.Contains(RPC_TRANSIENT_ERROR_CODES, message.Error.Code) {
+			return true, nil
+		}
+		errMessage := strings.ToLower(message.Error.Message)
+		if slices.ContainsFunc(RPC_TRANSIENT_ERROR_MESSAGES, func(transient string) bool {
+			return strings.Contains(errMessage, transient)
+		}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isDialError returns whether a request failed before reaching the node.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// ...
+
+type RetryPolicy struct {
+	MaxRetries       int64 // retries of a request (on any endpoint)
+	InitialBackoff   int64 // delay before the first retry in milliseconds (doubled on every retry)
+	MaxBackoff       int64 // maximum delay between retries in milliseconds
+	FailureThreshold int64 // consecutive failures opening the circuit of an endpoint
+	Cooldown         int64 // time an endpoint with an open circuit is skipped in milliseconds
+}
+
+type EndpointHealth struct {
+	URL     string // credentials are redacted
+	Healthy bool
+	ChainID types.ChainID
+	Latency int64 // milliseconds
+	Err     error
+}
+
+type rpcEndpoint struct {
+	url       *url.URL
+	failures  int64
+	openUntil time.Time
+	disabled  bool
+}
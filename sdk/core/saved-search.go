@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"log"
+	"maps"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// SavedSearchConfig is the configuration for a saved search.
+type SavedSearchConfig struct {
+	// Params is the search criteria of the saved search.
+	Params types.SearchParams
+
+	// PollInterval is the interval for checking new registry events in milliseconds.
+	PollInterval int64
+
+	// RefreshInterval is the interval for re-running the full search in milliseconds.
+	// The full search picks up subgraph updates without registry events (e.g. new URIs).
+	RefreshInterval int64
+
+	// EmitInitial emits an added event for every agent matching on start.
+	EmitInitial bool
+
+	// OnChange is called for every change (events are sent to the channel when unset).
+	OnChange func(event SavedSearchEvent)
+}
+
+// SavedSearch is a search that runs incrementally and emits agents that newly match
+// (or stop matching) the search criteria.
+type SavedSearch struct {
+	sdk    *SDK
+	config SavedSearchConfig
+	events chan SavedSearchEvent
+
+	mu        sync.Mutex
+	matches   map[types.AgentID]types.AgentSummary
+	pending   map[types.AgentID]bool // agents with events that are not indexed yet
+	lastBlock int64
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewSavedSearch creates a new saved search (call Start to begin watching).
+func (s *SDK) NewSavedSearch(config SavedSearchConfig) *SavedSearch {
+	if config.PollInterval == 0 {
+		config.PollInterval = utils.TIMEOUTS["SAVED_SEARCH_POLL"]
+	}
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = utils.TIMEOUTS["SAVED_SEARCH_REFRESH"]
+	}
+
+	return &SavedSearch{
+		sdk:     s,
+		config:  config,
+		events:  make(chan SavedSearchEvent, 100),
+		matches: map[types.AgentID]types.AgentSummary{},
+		pending: map[types.AgentID]bool{},
+	}
+}
+
+// Events returns the channel of changes (closed when the saved search stops).
+func (ss *SavedSearch) Events() <-chan SavedSearchEvent {
+	return ss.events
+}
+
+// Matches returns the agents currently matching the saved search.
+func (ss *SavedSearch) Matches() []types.AgentSummary {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	agentIDs := slices.Sorted(maps.Keys(ss.matches))
+	agents := make([]types.AgentSummary, 0, len(agentIDs))
+	for _, agentID := range agentIDs {
+		agents = append(agents, ss.matches[agentID])
+	}
+	return agents
+}
+
+// Start runs the initial search and watches for changes in the background.
+func (ss *SavedSearch) Start(ctx context.Context) {
+	ctx, ss.cancel = context.WithCancel(ctx)
+	ss.done = make(chan struct{})
+
+	if ss.watchesRegistryEvents() {
+		ss.lastBlock = ss.sdk.web3Client.GetBlockNumber()
+	}
+
+	go func() {
+		defer close(ss.done)
+		defer close(ss.events)
+
+		// Run the initial search in the background so that events are not sent before
+		// the caller starts reading from the channel
+		ss.refresh(ctx, ss.config.EmitInitial)
+
+		pollTicker := time.NewTicker(time.Duration(ss.config.PollInterval) * time.Millisecond)
+		defer pollTicker.Stop()
+		refreshTicker := time.NewTicker(time.Duration(ss.config.RefreshInterval) * time.Millisecond)
+		defer refreshTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-pollTicker.C:
+				ss.poll(ctx)
+			case <-refreshTicker.C:
+				ss.refresh(ctx, true)
+			}
+		}
+	}()
+}
+
+// Stop stops watching and waits for the background goroutine to exit.
+func (ss *SavedSearch) Stop() {
+	if ss.cancel == nil {
+		return
+	}
+	ss.cancel()
+	<-ss.done
+}
+
+// watchesRegistryEvents checks if registry events are available for the SDK chain.
+func (ss *SavedSearch) watchesRegistryEvents() bool {
+	if ss.sdk.registries["IDENTITY"] == "" || ss.sdk.GetSubgraphClient(ss.sdk.chainID) == nil {
+		return false
+	}
+	chains := ss.config.Params.Chains
+	return len(chains) == 0 || slices.Contains(chains, ss.sdk.chainID)
+}
+
+// refresh runs the full search and emits the differences with the current matches.
+func (ss *SavedSearch) refresh(ctx context.Context, emit bool) {
+	// A single page holds the results of all chains (each capped at SEARCH_MAX_RESULTS_PER_CHAIN)
+	pageSize := utils.DEFAULTS["SEARCH_MAX_RESULTS_PER_CHAIN"] * int64(max(len(ss.config.Params.Chains), 1))
+	result := ss.sdk.indexer.searchAgentsAcrossChains(ss.config.Params, nil, pageSize, "", 0)
+
+	// Keep previous matches of chains that failed to respond, and of chains with truncated
+	// results (their missing matches are not removals)
+	current := map[types.AgentID]types.AgentSummary{}
+	ss.mu.Lock()
+	for agentID, agent := range ss.matches {
+		if slices.Contains(result.Meta.FailedChains, agent.ChainID) ||
+			slices.Contains(result.Meta.TruncatedChains, agent.ChainID) {
+			current[agentID] = agent
+		}
+	}
+	ss.mu.Unlock()
+
+	for _, agent := range result.Items {
+		current[savedSearchKey(agent)] = agent
+	}
+
+	ss.mu.Lock()
+	previous := ss.matches
+	ss.matches = current
+	ss.mu.Unlock()
+
+	if !emit {
+		return
+	}
+
+	for agentID, agent := range current {
+		if _, ok := previous[agentID]; !ok {
+			ss.emit(ctx, SavedSearchEvent{Type: SAVED_SEARCH_EVENT_ADDED, Agent: agent})
+		}
+	}
+	for agentID, agent := range previous {
+		if _, ok := current[agentID]; !ok {
+			ss.emit(ctx, SavedSearchEvent{Type: SAVED_SEARCH_EVENT_REMOVED, Agent: agent})
+		}
+	}
+}
+
+// poll processes the Registered and MetadataSet events since the last poll and
+// re-evaluates only the affected agents.
+func (ss *SavedSearch) poll(ctx context.Context) {
+	if !ss.watchesRegistryEvents() {
+		return
+	}
+
+	latestBlock := ss.sdk.web3Client.GetBlockNumber()
+
+	affected := map[types.AgentID]bool{}
+	ss.mu.Lock()
+	maps.Copy(affected, ss.pending)
+	fromBlock := ss.lastBlock + 1
+	ss.mu.Unlock()
+
+	if fromBlock <= latestBlock {
+		identityRegistry := ss.sdk.GetIdentityRegistry()
+		for _, eventName := range []string{"Registered", "MetadataSet"} {
+			for _, event := range ss.sdk.web3Client.GetEvents(identityRegistry, eventName, fromBlock, latestBlock) {
+				if len(event.Topics) < 2 {
+					continue
+				}
+				tokenID := new(big.Int).SetBytes(event.Topics[1].Bytes())
+				affected[utils.FormattedAgentID(ss.sdk.chainID, tokenID.String())] = true
+			}
+		}
+	}
+
+	subgraphClient := ss.sdk.GetSubgraphClient(ss.sdk.chainID)
+	pending := map[types.AgentID]bool{}
+	for agentID := range affected {
+		agent, found, err := subgraphClient.FindAgentByID(agentID)
+		if err != nil {
+			log.Printf("warning: failed to get agent %s from subgraph, retrying on the next poll: %v", agentID, err)
+		}
+		if !found {
+			// The subgraph has not indexed the agent yet (or failed), retry on the next poll
+			pending[agentID] = true
+			continue
+		}
+		if agent.ChainID == 0 {
+			agent.ChainID = ss.sdk.chainID
+		}
+
+		if hasOASFFilters(ss.config.Params) {
+			agents := []types.AgentSummary{agent}
+			if err := subgraphClient.registrationFiles.Fill(agents); err != nil {
+				log.Printf("warning: failed to read the registration file of agent %s, retrying on the next poll: %v", agentID, err)
+				pending[agentID] = true
+				continue
+			}
+			agent = agents[0]
+		}
+
+		if ss.config.Params.IncludeReputation || ss.config.Params.HasReputationFilters() {
+			agents, err := ss.sdk.indexer.attachReputation(subgraphClient, []types.AgentSummary{agent}, ss.sdk.chainID)
+			if err != nil {
+				log.Printf("warning: failed to get reputation of agent %s, retrying on the next poll: %v", agentID, err)
+				pending[agentID] = true
+				continue
+			}
+			agent = agents[0]
+		}
+
+		ss.evaluate(ctx, agent)
+	}
+
+	ss.mu.Lock()
+	ss.pending = pending
+	ss.lastBlock = latestBlock
+	ss.mu.Unlock()
+}
+
+// evaluate checks a single agent against the search criteria and emits the change, if any.
+func (ss *SavedSearch) evaluate(ctx context.Context, agent types.AgentSummary) {
+	key := savedSearchKey(agent)
+	matches := MatchesSearchParams(agent, ss.config.Params)
+
+	ss.mu.Lock()
+	_, matched := ss.matches[key]
+	if matches {
+		ss.matches[key] = agent
+	} else {
+		delete(ss.matches, key)
+	}
+	ss.mu.Unlock()
+
+	if matches && !matched {
+		ss.emit(ctx, SavedSearchEvent{Type: SAVED_SEARCH_EVENT_ADDED, Agent: agent})
+	} else if !matches && matched {
+		ss.emit(ctx, SavedSearchEvent{Type: SAVED_SEARCH_EVENT_REMOVED, Agent: agent})
+	}
+}
+
+// emit sends the event to the callback or to the events channel.
+func (ss *SavedSearch) emit(ctx context.Context, event SavedSearchEvent) {
+	if ss.config.OnChange != nil {
+		ss.config.OnChange(event)
+		return
+	}
+
+	select {
+	case ss.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// savedSearchKey returns the key of an agent in the saved search matches (chainID:tokenID).
+func savedSearchKey(agent types.AgentSummary) types.AgentID {
+	return agentStatsID(agent.ChainID, agent)
+}
+
+// ...
+
+type SavedSearchEventType string
+
+const (
+	SAVED_SEARCH_EVENT_ADDED   SavedSearchEventType = "added"
+	SAVED_SEARCH_EVENT_REMOVED SavedSearchEventType = "removed"
+)
+
+type SavedSearchEvent struct {
+	Type  SavedSearchEventType
+	Agent types.AgentSummary
+}
@@ -2,16 +2,18 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"maps"
 	"math/big"
 	"net/http"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ryanchristo/agent0-go/sdk/caip"
 	"github.com/ryanchristo/agent0-go/sdk/types"
 	"github.com/ryanchristo/agent0-go/sdk/utils"
 )
@@ -20,9 +22,26 @@ import (
 type SDKConfig struct {
 	ChainID           types.ChainID
 	RPCURL            types.URI
-	Signer            any // string, wallet, or signer
+	RPCURLs           []types.URI // failover endpoints of the chain (after RPCURL when set)
+	RPCRetryPolicy    RetryPolicy // retries and circuit breaking of the RPC endpoints
+	Signer            any         // string (private key) or Signer
 	RegistryOverrides RegistryOverrides
 
+	// RegistryDeploymentBlock is the first block scanned for registry events (e.g. operator
+	// approvals), usually the deployment block of the registries (genesis when unset)
+	RegistryDeploymentBlock int64
+
+	// Transaction configuration
+
+	FeeStrategy  FeeStrategy // chain default when unset
+	MaxFeeBudget *big.Int    // maximum fee of a single transaction in wei (unlimited when unset)
+
+	TransactionReplacement *ReplacementOptions // replace stuck transactions (disabled when unset)
+
+	JournalPath string // directory of the transaction journal (disabled when unset)
+
+	Preflight bool // run the preflight checks before agent updates, transfers and feedback writes
+
 	// IPFS configuration
 
 	IPFS               IPFSProvider
@@ -49,6 +68,12 @@ type SDK struct {
 	registries         map[string]types.Address
 	chainID            types.ChainID
 	subgraphURLs       map[types.ChainID]string
+	registrationFiles  *RegistrationFileReader
+	deploymentBlock    int64
+	journal            *TransactionJournal
+	preflight          bool
+
+	mu sync.Mutex // guards the lazily initialized registries
 }
 
 // NewSDK creates a new SDK instance.
@@ -58,7 +83,25 @@ func NewSDK(cfg SDKConfig) *SDK {
 	sdk.chainID = cfg.ChainID
 
 	// Initialize web3 client
-	sdk.web3Client = NewWeb3Client(cfg.RPCURL, cfg.Signer)
+	rpcURLs := cfg.RPCURLs
+	if cfg.RPCURL != "" {
+		rpcURLs = append([]types.URI{cfg.RPCURL}, rpcURLs...)
+	}
+	sdk.web3Client = NewFailoverWeb3Client(rpcURLs, cfg.Signer, cfg.RPCRetryPolicy)
+	sdk.web3Client.FeeStrategy = cfg.FeeStrategy
+	sdk.web3Client.MaxFeeBudget = cfg.MaxFeeBudget
+	sdk.web3Client.Replacement = cfg.TransactionReplacement
+	sdk.preflight = cfg.Preflight
+	sdk.deploymentBlock = cfg.RegistryDeploymentBlock
+
+	// Initialize transaction journal
+	if cfg.JournalPath != "" {
+		journal, err := NewTransactionJournal(cfg.JournalPath)
+		if err != nil {
+			log.Fatalf("Failed to initialize transaction journal: %v", err)
+		}
+		sdk.journal = journal
+	}
 
 	// Resolve registry addresses
 	mergedRegistries := make(map[string]types.Address)
@@ -81,12 +124,15 @@ func NewSDK(cfg SDKConfig) *SDK {
 	}
 
 	// Initialize subgraph client
+	sdk.registrationFiles = NewRegistrationFileReader()
 	if resolvedSubgraphURL != "" {
-		sdk.subgraphClient = NewSubgraphClient(resolvedSubgraphURL)
+		sdk.subgraphClient = sdk.newSubgraphClient(resolvedSubgraphURL)
 	}
 
 	// Initialize indexer
 	sdk.indexer = NewAgentIndexer(sdk.web3Client, sdk.subgraphClient, sdk.subgraphURLs)
+	sdk.indexer.registrationFiles = sdk.registrationFiles
+	sdk.indexer.registryOverrides = cfg.RegistryOverrides
 
 	// Initialize IPFS client
 	if cfg.IPFS != "" {
@@ -183,7 +229,7 @@ func (s *SDK) GetSubgraphClient(chainID types.ChainID) *SubgraphClient {
 	}
 
 	if resolvedURL != "" {
-		return NewSubgraphClient(resolvedURL)
+		return s.newSubgraphClient(resolvedURL)
 	}
 
 	return nil
@@ -191,6 +237,9 @@ func (s *SDK) GetSubgraphClient(chainID types.ChainID) *SubgraphClient {
 
 // GetIdentityRegistry returns the identity registry contract.
 func (s *SDK) GetIdentityRegistry() *Contract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.identityRegistry == nil {
 		address := s.registries["IDENTITY"]
 		if address == "" {
@@ -203,6 +252,9 @@ func (s *SDK) GetIdentityRegistry() *Contract {
 
 // GetReputationRegistry returns the reputation registry contract.
 func (s *SDK) GetReputationRegistry() *Contract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.reputationRegistry == nil {
 		address := s.registries["REPUTATION"]
 		if address == "" {
@@ -218,6 +270,9 @@ func (s *SDK) GetReputationRegistry() *Contract {
 
 // GetValidationRegistry returns the validation registry contract.
 func (s *SDK) GetValidationRegistry() *Contract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.validationRegistry == nil {
 		address := s.registries["VALIDATION"]
 		if address == "" {
@@ -243,49 +298,116 @@ func (s *SDK) CreateAgent(name, description, imageURL string) *Agent {
 		Image:       imageURL,
 		Endpoints:   []types.Endpoint{},
 		TrustModels: []types.TrustModel{},
-		Owners:      []string{},
-		Operators:   []string{},
+		Owners:      []types.Address{},
+		Operators:   []types.Address{},
 		Active:      false,
 		X402Support: false,
 		Metadata:    map[string]any{},
 		UpdatedAt:   time.Now().Unix(),
 	}
-	return newAgent(registrationFile)
+	return newAgent(s, registrationFile)
 }
 
 // LoadAgent loads an existing agent (hydrates from registration file if registered).
 func (s *SDK) LoadAgent(agentID types.AgentID) *Agent {
 	// Parse agent ID
-	parsedAgentID := utils.ParseAgentID(agentID)
-	chainID := parsedAgentID.ChainID
-	tokenID := parsedAgentID.TokenID
-
-	currentChainID := s.ChainID()
-	if chainID != currentChainID {
-		log.Fatalf("agent %s is not on current chain %d", agentID, currentChainID)
-	}
+	tokenID := s.tokenID(agentID)
 
 	// Get token URI from contract
 	tokenURI := ""
 	identityRegistry := s.GetIdentityRegistry()
 	if identityRegistry != nil {
-		tokenURI = s.web3Client.CallContract(identityRegistry, "tokenURI", tokenID).(string)
+		var err error
+		tokenURI, err = callContract(
+			identityRegistry,
+			identityRegistryBinding.PackTokenURI(tokenID),
+			identityRegistryBinding.UnpackTokenURI,
+		)
+		if err != nil {
+			log.Fatalf("Failed to get token URI of agent %s: %v", agentID, err)
+		}
 	} else {
-		log.Fatalf("identity registry not found for chain %d", currentChainID)
+		log.Fatalf("identity registry not found for chain %d", s.ChainID())
 	}
 
-	// Load registration file - handle empty URI (agent registered without URI)
-	registrationFile := types.RegistrationFile{}
-	if tokenURI == "" {
-		registrationFile = s.createEmptyRegistrationFile()
-	} else {
-		registrationFile = s.loadRegistrationFile(tokenURI)
+	return s.loadAgentFromURI(agentID, tokenURI)
+}
+
+// LoadAgents loads existing agents of the current chain, reading their token URIs in
+// batches (see Web3Client.BatchCall).
+func (s *SDK) LoadAgents(agentIDs []types.AgentID) []*Agent {
+	identityRegistry := s.GetIdentityRegistry()
+	if identityRegistry == nil {
+		log.Fatalf("identity registry not found for chain %d", s.ChainID())
 	}
 
-	registrationFile.AgentID = agentID
-	registrationFile.AgentURI = tokenURI
+	calls := make([]ReadCall, len(agentIDs))
+	for idx, agentID := range agentIDs {
+		calls[idx] = ReadCall{identityRegistry, identityRegistryBinding.PackTokenURI(s.tokenID(agentID))}
+	}
+	tokenURIs, errs := batchRead(s.web3Client, calls, identityRegistryBinding.UnpackTokenURI)
+
+	agents := make([]*Agent, len(agentIDs))
+	for idx, agentID := range agentIDs {
+		if errs[idx] != nil {
+			log.Fatalf("Failed to get token URI of agent %s: %v", agentID, errs[idx])
+		}
+		agents[idx] = s.loadAgentFromURI(agentID, tokenURIs[idx])
+	}
+	return agents
+}
+
+// IncompleteFlows returns the flows of the transaction journal that were interrupted
+// (e.g. by a crash) and can be resumed with ResumeFlow.
+func (s *SDK) IncompleteFlows() []*JournalFlow {
+	if s.journal == nil {
+		return []*JournalFlow{}
+	}
+	return s.journal.IncompleteFlows()
+}
+
+// ResumeFlow resumes an incomplete flow of the transaction journal and returns the
+// registration file of the agent.
+func (s *SDK) ResumeFlow(flowID string) types.RegistrationFile {
+	if s.journal == nil {
+		log.Fatal("Cannot resume flow: no transaction journal configured")
+	}
 
-	return newAgent(registrationFile)
+	flow, ok := s.journal.Get(flowID)
+	if !ok {
+		log.Fatalf("Flow %s not found in transaction journal", flowID)
+	}
+	if flow.Status != JOURNAL_FLOW_STATUS_IN_PROGRESS {
+		log.Fatalf("Flow %s is not in progress (%s)", flowID, flow.Status)
+	}
+	if flow.ChainID != s.ChainID() {
+		log.Fatalf("Flow %s is not on current chain %d", flowID, s.ChainID())
+	}
+
+	// Restore the agent of the flow (the data was decoded from JSON)
+	content, err := json.Marshal(flow.Data["registrationFile"])
+	if err != nil {
+		log.Fatalf("Failed to read flow registration file: %v", err)
+	}
+	registrationFile := types.RegistrationFile{}
+	if err := json.Unmarshal(content, &registrationFile); err != nil {
+		log.Fatalf("Failed to read flow registration file: %v", err)
+	}
+	agent := newAgent(s, registrationFile)
+
+	switch flow.Kind {
+	case JOURNAL_FLOW_REGISTER_IPFS:
+		return agent.RegisterIPFS()
+	case JOURNAL_FLOW_REGISTER_HTTP:
+		agentURI, _ := flow.Data["agentUri"].(string)
+		return agent.RegisterHTTP(agentURI)
+	case JOURNAL_FLOW_REGISTER_MULTI_CHAIN:
+		log.Fatalf("Cannot resume flow %s alone: call Agent.RegisterOnChains again with the same chains", flowID)
+		return types.RegistrationFile{}
+	default:
+		log.Fatalf("Cannot resume flow of kind %s", flow.Kind)
+		return types.RegistrationFile{}
+	}
 }
 
 // GetAgent gets an agent summary from the subgraph (read-only).
@@ -299,7 +421,7 @@ func (s *SDK) GetAgent(agentID types.AgentID) types.AgentSummary {
 	if strings.Contains(agentID, ":") {
 		parsed := utils.ParseAgentID(agentID)
 		parsedChainID = parsed.ChainID
-		formattedAgentID = agentID
+		formattedAgentID = utils.NormalizeAgentID(agentID) // subgraph IDs are chainID:tokenID
 	} else {
 		parsedChainID = s.chainID
 		formattedAgentID = utils.FormattedAgentID(s.chainID, agentID)
@@ -386,20 +508,54 @@ func (s *SDK) TransferAgent(agentID types.AgentID, newOwner types.Address) Trans
 	return agent.Transfer(newOwner)
 }
 
+// TryTransferAgent transfers agent ownership and returns failures as error (see
+// Agent.TryTransfer).
+func (s *SDK) TryTransferAgent(agentID types.AgentID, newOwner types.Address) (TransferResult, error) {
+	agent := s.LoadAgent(agentID)
+	return agent.TryTransfer(newOwner)
+}
+
+// GetAgentOwners gets the current owner addresses of agents of the current chain, reading
+// them in batches. Agents that do not exist are omitted.
+func (s *SDK) GetAgentOwners(agentIDs []types.AgentID) map[types.AgentID]types.Address {
+	identityRegistry := s.GetIdentityRegistry()
+
+	calls := make([]ReadCall, len(agentIDs))
+	for idx, agentID := range agentIDs {
+		calls[idx] = ReadCall{identityRegistry, identityRegistryBinding.PackOwnerOf(s.tokenID(agentID))}
+	}
+	owners, errs := batchRead(s.web3Client, calls, identityRegistryBinding.UnpackOwnerOf)
+
+	result := map[types.AgentID]types.Address{}
+	for idx, agentID := range agentIDs {
+		if errs[idx] != nil {
+			if !errors.Is(errs[idx], ErrAgentNotFound) {
+				log.Fatalf("Failed to get owner of agent %s: %v", agentID, errs[idx])
+			}
+			continue
+		}
+		result[agentID] = types.AddressFromCommon(owners[idx])
+	}
+	return result
+}
+
 // IsAgentOwner checks if the given address is the owner of the agent.
 func (s *SDK) IsAgentOwner(agentID types.AgentID, address types.Address) bool {
-	tokenID := utils.ParseAgentID(agentID).TokenID
-	identityRegistry := s.GetIdentityRegistry()
-	owner := s.web3Client.CallContract(identityRegistry, "ownerOf", big.NewInt(tokenID)).(string)
-	return strings.EqualFold(owner, address)
+	return s.GetAgentOwner(agentID).Equal(address)
 }
 
 // GetAgentOwner gets the current owner address of the agent.
 func (s *SDK) GetAgentOwner(agentID types.AgentID) types.Address {
 	tokenID := utils.ParseAgentID(agentID).TokenID
-	identityRegistry := s.GetIdentityRegistry()
-	owner := s.web3Client.CallContract(identityRegistry, "ownerOf", big.NewInt(tokenID)).(string)
-	return types.Address(owner)
+	owner, err := callContract(
+		s.GetIdentityRegistry(),
+		identityRegistryBinding.PackOwnerOf(tokenID),
+		identityRegistryBinding.UnpackOwnerOf,
+	)
+	if err != nil {
+		log.Fatalf("Failed to get owner of agent %s: %v", agentID, err)
+	}
+	return types.AddressFromCommon(owner)
 }
 
 // Feedback methods
@@ -445,10 +601,31 @@ func (s *SDK) GiveFeedback(
 	feedbackFile map[string]any,
 	feedbackAuth string,
 ) Feedback {
+	feedback, err := s.TryGiveFeedback(agentID, feedbackFile, feedbackAuth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return feedback
+}
+
+// TryGiveFeedback submits feedback on-chain and returns failures as error (e.g. reverts
+// matched with errors.Is(err, ErrFeedbackAuthExpired)).
+func (s *SDK) TryGiveFeedback(
+	agentID types.AgentID,
+	feedbackFile map[string]any,
+	feedbackAuth string,
+) (Feedback, error) {
 	// Update feedback manager with registries
 	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
 	s.feedbackManager.SetIdentityRegistry(s.GetIdentityRegistry())
 
+	err := s.checkPreflight("feedback", func(preflight *Preflight) PreflightResult {
+		return preflight.GiveFeedback(agentID, feedbackFile, feedbackAuth)
+	})
+	if err != nil {
+		return Feedback{}, err
+	}
+
 	return s.feedbackManager.GiveFeedback(agentID, feedbackFile, "", feedbackAuth)
 }
 
@@ -458,17 +635,29 @@ func (s *SDK) GetFeedback(
 	clientAddress types.Address,
 	feedbackIndex int64,
 ) Feedback {
+	// Update feedback manager with registries
+	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
+
 	return s.feedbackManager.GetFeedback(agentID, clientAddress, feedbackIndex)
 }
 
+// GetFeedbacks reads feedback entries of agents of the current chain from the contract,
+// reading them in batches.
+func (s *SDK) GetFeedbacks(refs []FeedbackRef) []Feedback {
+	// Update feedback manager with registries
+	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
+
+	return s.feedbackManager.GetFeedbacks(refs)
+}
+
 // SearchFeedback searches for feedback entries with the given filters.
 func (s *SDK) SearchFeedback(
 	agentID types.AgentID,
 	tags []string,
 	capabilities []string,
 	skills []string,
-	minScore int64,
-	maxScore int64,
+	minScore *int64,
+	maxScore *int64,
 ) []types.Feedback {
 	params := types.SearchFeedbackParams{
 		Agents:       []types.AgentID{agentID},
@@ -488,9 +677,31 @@ func (s *SDK) AppendResponse(
 	feedbackIndex int64,
 	response FeedbackResponse,
 ) string {
+	txHash, err := s.TryAppendResponse(agentID, clientAddress, feedbackIndex, response)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return txHash
+}
+
+// TryAppendResponse appends a response to feedback and returns the transaction hash,
+// returning failures as error.
+func (s *SDK) TryAppendResponse(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+	response FeedbackResponse,
+) (string, error) {
 	// Update feedback manager with registries
 	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
 
+	err := s.checkPreflight("response", func(preflight *Preflight) PreflightResult {
+		return preflight.AppendResponse(agentID, clientAddress, feedbackIndex, response)
+	})
+	if err != nil {
+		return "", err
+	}
+
 	return s.feedbackManager.AppendResponse(agentID, clientAddress, feedbackIndex, response.URI, response.Hash)
 }
 
@@ -499,9 +710,29 @@ func (s *SDK) RevokeFeedback(
 	agentID types.AgentID,
 	feedbackIndex int64,
 ) string {
+	txHash, err := s.TryRevokeFeedback(agentID, feedbackIndex)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return txHash
+}
+
+// TryRevokeFeedback revokes feedback and returns the transaction hash, returning failures
+// as error.
+func (s *SDK) TryRevokeFeedback(
+	agentID types.AgentID,
+	feedbackIndex int64,
+) (string, error) {
 	// Update feedback manager with registries
 	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
 
+	err := s.checkPreflight("feedback revocation", func(preflight *Preflight) PreflightResult {
+		return preflight.RevokeFeedback(agentID, feedbackIndex)
+	})
+	if err != nil {
+		return "", err
+	}
+
 	return s.feedbackManager.RevokeFeedback(agentID, feedbackIndex)
 }
 
@@ -517,8 +748,75 @@ func (s *SDK) GetReputationSummary(
 	return s.feedbackManager.GetReputationSummary(agentID, tag1, tag2)
 }
 
+// GetReputationSummaries gets the reputation summaries of agents with a specific tag,
+// reading them in batches.
+func (s *SDK) GetReputationSummaries(
+	agentIDs []types.AgentID,
+	tag1 string,
+	tag2 string,
+) map[types.AgentID]ReputationSummary {
+	// Update feedback manager with registries
+	s.feedbackManager.SetReputationRegistry(s.GetReputationRegistry())
+
+	return s.feedbackManager.GetReputationSummaries(agentIDs, tag1, tag2)
+}
+
 // Private methods
 
+// loadAgentFromURI creates an agent instance from its token URI (the registration file is
+// loaded from the URI, an empty one is created for agents registered without URI).
+func (s *SDK) loadAgentFromURI(agentID types.AgentID, tokenURI string) *Agent {
+	registrationFile := types.RegistrationFile{}
+	if tokenURI == "" {
+		registrationFile = s.createEmptyRegistrationFile()
+	} else {
+		registrationFile = s.loadRegistrationFile(tokenURI)
+	}
+
+	registrationFile.AgentID = utils.NormalizeAgentID(agentID)
+	registrationFile.AgentURI = tokenURI
+
+	return newAgent(s, registrationFile)
+}
+
+// tokenID returns the token ID of an agent of the current chain (and of the identity
+// registry of the SDK, for global agent IDs).
+func (s *SDK) tokenID(agentID types.AgentID) *big.Int {
+	parsedAgentID := utils.ParseAgentID(agentID)
+	if parsedAgentID.ChainID != s.ChainID() {
+		log.Fatalf("agent %s is not on current chain %d", agentID, s.ChainID())
+	}
+	if parsedAgentID.Registry != "" && !parsedAgentID.Registry.Equal(s.registries["IDENTITY"]) {
+		log.Fatalf("agent %s is not in identity registry %s", agentID, s.registries["IDENTITY"])
+	}
+	return parsedAgentID.TokenID
+}
+
+// newSubgraphClient creates a subgraph client sharing the registration file cache of the SDK.
+func (s *SDK) newSubgraphClient(url string) *SubgraphClient {
+	client := NewSubgraphClient(url)
+	client.registrationFiles = s.registrationFiles
+	return client
+}
+
+// beginFlow returns the incomplete flow of the given kind and key from the transaction
+// journal, or starts a new one (nil without journal).
+func (s *SDK) beginFlow(kind JournalFlowKind, key string, registrationFile types.RegistrationFile) *JournalFlow {
+	if s.journal == nil {
+		return nil
+	}
+
+	if flow, ok := s.journal.Find(kind, key); ok {
+		log.Printf("warning: resuming incomplete %s flow %s", kind, flow.ID)
+		flow.SetData("registrationFile", registrationFile)
+		return flow
+	}
+
+	return s.journal.Begin(kind, key, s.ChainID(), map[string]any{
+		"registrationFile": registrationFile,
+	})
+}
+
 // createEmptyRegistrationFile creates an empty registration file with default values.
 func (s *SDK) createEmptyRegistrationFile() types.RegistrationFile {
 	return types.RegistrationFile{
@@ -526,8 +824,8 @@ func (s *SDK) createEmptyRegistrationFile() types.RegistrationFile {
 		Description: "",
 		Endpoints:   []types.Endpoint{},
 		TrustModels: []types.TrustModel{},
-		Owners:      []string{},
-		Operators:   []string{},
+		Owners:      []types.Address{},
+		Operators:   []types.Address{},
 		Active:      false,
 		X402Support: false,
 		Metadata:    map[string]any{},
@@ -629,8 +927,8 @@ func (s *SDK) transformRegistrationFile(rawData map[string]any) types.Registrati
 		Image:       rawData["image"].(string),
 		Endpoints:   endpoints,
 		TrustModels: trustModels,
-		Owners:      rawData["owners"].([]string),
-		Operators:   rawData["operators"].([]string),
+		Owners:      types.AddressList(rawData["owners"].([]string)),
+		Operators:   types.AddressList(rawData["operators"].([]string)),
 		Active:      rawData["active"].(bool),
 		X402Support: rawData["x402Support"].(bool),
 		Metadata: map[string]any{
@@ -640,7 +938,38 @@ func (s *SDK) transformRegistrationFile(rawData map[string]any) types.Registrati
 		UpdatedAt:     rawData["updatedAt"].(int64),
 		WalletAddress: walletAddress,
 		WalletChainID: walletChainID,
+		Registrations: transformRegistrations(rawData),
+	}
+}
+
+// transformRegistrations transforms the ERC-8004 registrations ({ agentId, agentRegistry })
+// from the raw data to global agent IDs. Invalid registrations are skipped.
+func transformRegistrations(rawData map[string]any) []string {
+	rawRegistrations, _ := rawData["registrations"].([]any)
+
+	registrations := []string{}
+	for _, rawRegistration := range rawRegistrations {
+		registration, _ := rawRegistration.(map[string]any)
+		registry, _ := registration["agentRegistry"].(string)
+
+		tokenID := ""
+		switch value := registration["agentId"].(type) {
+		case string:
+			tokenID = value
+		case float64:
+			tokenID = strconv.FormatFloat(value, 'f', -1, 64)
+		case json.Number:
+			tokenID = value.String()
+		}
+
+		agentID, err := caip.ParseAgentID(registry + ":" + tokenID)
+		if err != nil || agentID.Registry == "" {
+			log.Printf("warning: skipping invalid registration %v: %v", rawRegistration, err)
+			continue
+		}
+		registrations = append(registrations, agentID.String())
 	}
+	return registrations
 }
 
 // transformEndpoints transforms the endpoints from the raw data to the RegistrationFile format.
@@ -694,14 +1023,11 @@ func (s *SDK) transformEndpointLegacy(endpoint map[string]any, rawData map[strin
 
 		// Special handling for wallet endpoints - parse eip155 format
 		if endpointType == string(types.ENDPOINT_TYPE_WALLET) {
-			walletMatch := regexp.MustCompile(`eip155:(\d+):(0x[a-fA-F0-9]{40})`).FindStringSubmatch(value)
-			if walletMatch != nil {
-				walletChainID, err := strconv.ParseInt(walletMatch[1], 10, 64)
-				if err != nil {
-					log.Fatalf("Failed to parse wallet chain ID: %v", err)
+			if wallet, err := caip.ParseAccountID(value); err == nil {
+				if walletChainID, err := wallet.Chain.EVMChainID(); err == nil {
+					rawData["walletAddress"] = wallet.Address
+					rawData["walletChainID"] = walletChainID
 				}
-				rawData["walletAddress"] = walletMatch[2]
-				rawData["walletChainID"] = walletChainID
 			}
 		}
 	} else {
@@ -725,8 +1051,8 @@ func (s *SDK) extractWalletInfo(rawData map[string]any) WalletInfo {
 	if walletAddress, ok := rawData["walletAddress"].(string); ok {
 		if walletChainID, ok := rawData["walletChainID"].(int64); ok {
 			return WalletInfo{
-				WalletAddress: walletAddress,
-				ChainID:       walletChainID,
+				WalletAddress: types.Address(walletAddress),
+				ChainID:       types.ChainID(walletChainID),
 			}
 		}
 	}
@@ -757,6 +1083,6 @@ type RegistryOverrides = map[types.ChainID]map[string]types.Address
 type SubgraphOverrides = map[types.ChainID]string
 
 type WalletInfo struct {
-	WalletAddress string
-	ChainID       int64
+	WalletAddress types.Address
+	ChainID       types.ChainID
 }
@@ -0,0 +1,381 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// SearchQuery is the result of parsing a textual search query.
+type SearchQuery struct {
+	Params types.SearchParams
+	Sort   []string
+}
+
+// SearchQueryError is the error returned when a textual search query cannot be parsed.
+type SearchQueryError struct {
+	Position int    // byte offset of the term in the query
+	Term     string // the term that failed to parse
+	Message  string
+}
+
+// Error returns the error message.
+func (e *SearchQueryError) Error() string {
+	return fmt.Sprintf("invalid search query at position %d (%q): %s", e.Position, e.Term, e.Message)
+}
+
+// SEARCH_QUERY_SORT_FIELDS are the fields supported by the "sort:" filter.
+var SEARCH_QUERY_SORT_FIELDS = append([]string{"name", "chainId"}, REPUTATION_SORT_FIELDS...)
+
+// ParseSearchQuery parses a textual search query into search parameters and sort order.
+//
+// The query is a list of whitespace-separated terms. Terms without a filter are joined
+// into the name search criteria (they cannot be combined with name:); quoted terms may
+// contain whitespace. Supported filters:
+//
+//	name:<text> description:<text> ens:<name> did:<did>
+//	mcp:<bool> a2a:<bool> active:<bool> x402:<bool> reputation:<bool>
+//	chain:<id> owner:<address> operator:<address> wallet:<address> trust:<model>
+//	skill:<oasf skill> domain:<oasf domain> a2aSkill:<skill> tool:<name> prompt:<name> resource:<name>
+//	score<op><0-100> validationScore<op><0-100> feedback<op><n> validations<op><n>
+//	sort:<field>[:asc|desc]
+//
+// List filters may be repeated or given comma-separated values. Comparison operators
+// are >=, >, <= and < (score also supports =). For example:
+//
+//	mcp:true skill:summarization owner:0xabc... chain:84532 score>=80 "travel booking"
+func ParseSearchQuery(query string) (SearchQuery, error) {
+	result := SearchQuery{}
+	params := &result.Params
+
+	terms, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+
+	freeText := []string{}
+	for _, term := range terms {
+		fail := func(format string, args ...any) error {
+			return &SearchQueryError{Position: term.position, Term: term.raw, Message: fmt.Sprintf(format, args...)}
+		}
+
+		// Free text is the name criteria, so it cannot be combined with a name filter
+		isName := term.operator == ":" && strings.EqualFold(term.key, "name")
+		if (term.key == "" && params.Name != "") || (isName && len(freeText) > 0) {
+			return SearchQuery{}, fail("free text cannot be combined with name: (quote the name instead)")
+		}
+
+		if term.key == "" {
+			freeText = append(freeText, term.value)
+			continue
+		}
+
+		if term.value == "" {
+			return SearchQuery{}, fail("missing value for %q", term.key)
+		}
+
+		// Comparison filters (reputation)
+		if term.operator != ":" {
+			if err := applySearchQueryComparison(params, term); err != nil {
+				return SearchQuery{}, err
+			}
+			continue
+		}
+
+		switch strings.ToLower(term.key) {
+		case "name":
+			params.Name = term.value
+		case "description", "desc":
+			params.Description = term.value
+		case "ens":
+			params.ENS = term.value
+		case "did":
+			params.DID = term.value
+		case "mcp", "a2a", "active", "x402", "x402support", "reputation":
+			value, err := strconv.ParseBool(term.value)
+			if err != nil {
+				return SearchQuery{}, fail("expected true or false, got %q", term.value)
+			}
+			switch strings.ToLower(term.key) {
+			case "mcp":
+				params.MCP = &value
+			case "a2a":
+				params.A2A = &value
+			case "active":
+				params.Active = &value
+			case "x402", "x402support":
+				params.X402Support = &value
+			case "reputation":
+				params.IncludeReputation = value
+			}
+		case "chain":
+			for _, value := range splitSearchQueryList(term.value) {
+				chainID, err := types.ParseChainID(value)
+				if err != nil {
+					return SearchQuery{}, fail("invalid chain ID %q", value)
+				}
+				params.Chains = append(params.Chains, chainID)
+			}
+		case "owner", "operator", "wallet":
+			for _, value := range splitSearchQueryList(term.value) {
+				address, err := types.ParseAddress(value)
+				if err != nil {
+					return SearchQuery{}, fail("invalid address %q", value)
+				}
+				switch strings.ToLower(term.key) {
+				case "owner":
+					params.Owners = append(params.Owners, address)
+				case "operator":
+					params.Operators = append(params.Operators, address)
+				case "wallet":
+					if params.WalletAddress != "" {
+						return SearchQuery{}, fail("only one wallet address is supported")
+					}
+					params.WalletAddress = address
+				}
+			}
+		case "trust":
+			for _, value := range splitSearchQueryList(term.value) {
+				trustModel := types.TrustModel(value)
+				if !slices.Contains([]types.TrustModel{
+					types.TRUST_MODEL_REPUTATION,
+					types.TRUST_MODEL_CRYPTO_ECONOMICS,
+					types.TRUST_MODEL_TEE_ATTESTATION,
+				}, trustModel) {
+					return SearchQuery{}, fail("unknown trust model %q", value)
+				}
+				params.SupportedTrust = append(params.SupportedTrust, trustModel)
+			}
+		case "skill":
+			params.OASFSkills = append(params.OASFSkills, splitSearchQueryList(term.value)...)
+		case "domain":
+			params.OASFDomains = append(params.OASFDomains, splitSearchQueryList(term.value)...)
+		case "a2askill":
+			params.A2ASkills = append(params.A2ASkills, splitSearchQueryList(term.value)...)
+		case "tool":
+			params.MCPTools = append(params.MCPTools, splitSearchQueryList(term.value)...)
+		case "prompt":
+			params.MCPPrompts = append(params.MCPPrompts, splitSearchQueryList(term.value)...)
+		case "resource":
+			params.MCPResources = append(params.MCPResources, splitSearchQueryList(term.value)...)
+		case "sort":
+			field, direction, hasDirection := strings.Cut(term.value, ":")
+			if !slices.Contains(SEARCH_QUERY_SORT_FIELDS, field) {
+				return SearchQuery{}, fail("unknown sort field %q (expected one of %s)",
+					field, strings.Join(SEARCH_QUERY_SORT_FIELDS, ", "))
+			}
+			if !hasDirection {
+				direction = string(ORDER_DIRECTION_DESC)
+				if field == "name" {
+					direction = string(ORDER_DIRECTION_ASC)
+				}
+			}
+			direction = strings.ToLower(direction)
+			if direction != string(ORDER_DIRECTION_ASC) && direction != string(ORDER_DIRECTION_DESC) {
+				return SearchQuery{}, fail("invalid sort direction %q (expected asc or desc)", direction)
+			}
+			result.Sort = append(result.Sort, field+":"+direction)
+		default:
+			return SearchQuery{}, fail("unknown filter %q", term.key)
+		}
+	}
+
+	if len(freeText) > 0 {
+		params.Name = strings.Join(freeText, " ")
+	}
+
+	return result, nil
+}
+
+// applySearchQueryComparison applies a reputation comparison term to the search parameters.
+func applySearchQueryComparison(params *types.SearchParams, term searchQueryTerm) error {
+	fail := func(format string, args ...any) error {
+		return &SearchQueryError{Position: term.position, Term: term.raw, Message: fmt.Sprintf(format, args...)}
+	}
+
+	value, err := strconv.ParseInt(term.value, 10, 64)
+	if err != nil || value < 0 {
+		return fail("expected a non-negative integer, got %q", term.value)
+	}
+
+	key := strings.ToLower(term.key)
+	isScore := key == "score" || key == "validationscore"
+	if isScore && value > 100 {
+		return fail("score must be between 0 and 100, got %d", value)
+	}
+
+	// Normalize strict comparisons to inclusive bounds: counts are integers, average
+	// scores are not (score>80 excludes exactly 80 but keeps 80.5)
+	if isScore {
+		score := float64(value)
+		var minimum, maximum *float64
+		switch term.operator {
+		case ">=":
+			minimum = &score
+		case ">":
+			above := math.Nextafter(score, math.Inf(1))
+			minimum = &above
+		case "<=":
+			maximum = &score
+		case "<":
+			if value == 0 {
+				return fail("score upper bound must be greater than 0")
+			}
+			below := math.Nextafter(score, math.Inf(-1))
+			maximum = &below
+		case "=":
+			minimum, maximum = &score, &score
+		}
+		if key == "validationscore" {
+			if maximum != nil {
+				return fail("only >= and > are supported for %q", term.key)
+			}
+			params.MinAverageValidationScore = minimum
+			return nil
+		}
+		if minimum != nil {
+			params.MinAverageScore = minimum
+		}
+		if maximum != nil {
+			params.MaxAverageScore = maximum
+		}
+		return nil
+	}
+
+	var minimum, maximum *int64
+	switch term.operator {
+	case ">=":
+		minimum = &value
+	case ">":
+		value++
+		minimum = &value
+	case "<=":
+		maximum = &value
+	case "<":
+		value--
+		maximum = &value
+	case "=":
+		minimum, maximum = &value, &value
+	}
+
+	switch key {
+	case "feedback", "validations":
+		if maximum != nil {
+			return fail("only >= and > are supported for %q", term.key)
+		}
+		if key == "feedback" {
+			params.MinFeedbackCount = minimum
+		} else {
+			params.MinCompletedValidations = minimum
+		}
+	default:
+		return fail("unknown comparison filter %q", term.key)
+	}
+
+	return nil
+}
+
+// tokenizeSearchQuery splits a textual search query into terms. The query is walked by
+// runes (positions are byte offsets).
+func tokenizeSearchQuery(query string) ([]searchQueryTerm, error) {
+	terms := []searchQueryTerm{}
+
+	position := 0
+	for position < len(query) {
+		char, size := utf8.DecodeRuneInString(query[position:])
+		if unicode.IsSpace(char) {
+			position += size
+			continue
+		}
+
+		start := position
+		raw := strings.Builder{}
+		text := strings.Builder{}
+		quoted := false
+		for position < len(query) {
+			char, size := utf8.DecodeRuneInString(query[position:])
+			if !quoted && unicode.IsSpace(char) {
+				break
+			}
+			// Runes are copied as written (invalid UTF-8 bytes are kept as is)
+			bytes := query[position : position+size]
+			raw.WriteString(bytes)
+			position += size
+
+			switch {
+			case char == '\\' && quoted && position < len(query):
+				_, size := utf8.DecodeRuneInString(query[position:])
+				raw.WriteString(query[position : position+size])
+				text.WriteString(query[position : position+size])
+				position += size
+			case char == '"':
+				quoted = !quoted
+			default:
+				text.WriteString(bytes)
+			}
+		}
+
+		if quoted {
+			return nil, &SearchQueryError{Position: start, Term: raw.String(), Message: "unterminated quoted string"}
+		}
+
+		terms = append(terms, parseSearchQueryTerm(start, raw.String(), text.String()))
+	}
+
+	return terms, nil
+}
+
+// parseSearchQueryTerm splits a term into its key, operator and value.
+// Terms starting with a quote are always free text.
+func parseSearchQueryTerm(position int, raw, text string) searchQueryTerm {
+	term := searchQueryTerm{position: position, raw: raw, value: text}
+	if strings.HasPrefix(raw, "\"") {
+		return term
+	}
+
+	// The key is the leading identifier of the raw term
+	keyEnd := strings.IndexFunc(raw, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if keyEnd <= 0 {
+		return term
+	}
+
+	for _, operator := range []string{">=", "<=", ":", ">", "<", "="} {
+		if strings.HasPrefix(raw[keyEnd:], operator) {
+			term.key = raw[:keyEnd]
+			term.operator = operator
+			term.value = text[keyEnd+len(operator):]
+			return term
+		}
+	}
+
+	return term
+}
+
+// splitSearchQueryList splits a comma-separated list value, skipping empty entries.
+func splitSearchQueryList(value string) []string {
+	values := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+// ...
+
+type searchQueryTerm struct {
+	position int
+	raw      string
+	key      string
+	operator string
+	value    string
+}
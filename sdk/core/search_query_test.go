@@ -0,0 +1,222 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+func TestTokenizeSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []searchQueryTerm
+	}{
+		{
+			name:  "empty",
+			query: "  \t ",
+			want:  []searchQueryTerm{},
+		},
+		{
+			name:  "free text",
+			query: "travel  booking",
+			want: []searchQueryTerm{
+				{position: 0, raw: "travel", value: "travel"},
+				{position: 8, raw: "booking", value: "booking"},
+			},
+		},
+		{
+			name:  "filters and comparisons",
+			query: "mcp:true score>=80 feedback>3",
+			want: []searchQueryTerm{
+				{position: 0, raw: "mcp:true", key: "mcp", operator: ":", value: "true"},
+				{position: 9, raw: "score>=80", key: "score", operator: ">=", value: "80"},
+				{position: 19, raw: "feedback>3", key: "feedback", operator: ">", value: "3"},
+			},
+		},
+		{
+			name:  "quoted values",
+			query: `name:"travel agent" "a \"quoted\" text"`,
+			want: []searchQueryTerm{
+				{position: 0, raw: `name:"travel agent"`, key: "name", operator: ":", value: "travel agent"},
+				{position: 20, raw: `"a \"quoted\" text"`, value: `a "quoted" text`},
+			},
+		},
+		{
+			name:  "multi-byte runes",
+			query: "voilà name:café",
+			want: []searchQueryTerm{
+				{position: 0, raw: "voilà", value: "voilà"},
+				{position: 7, raw: "name:café", key: "name", operator: ":", value: "café"},
+			},
+		},
+		{
+			name:  "unicode whitespace",
+			query: "été agent　naïve",
+			want: []searchQueryTerm{
+				{position: 0, raw: "été", value: "été"},
+				{position: 7, raw: "agent", value: "agent"},
+				{position: 15, raw: "naïve", value: "naïve"},
+			},
+		},
+		{
+			name:  "invalid utf-8",
+			query: "a\xffb c",
+			want: []searchQueryTerm{
+				{position: 0, raw: "a\xffb", value: "a\xffb"},
+				{position: 4, raw: "c", value: "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenizeSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("tokenizeSearchQuery(%q) error: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeSearchQueryUnterminated(t *testing.T) {
+	_, err := tokenizeSearchQuery(`agent name:"travel`)
+
+	var queryErr *SearchQueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("expected SearchQueryError, got %v", err)
+	}
+	if queryErr.Position != 6 {
+		t.Errorf("Position = %d, want 6", queryErr.Position)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	yes := true
+	score := func(value float64) *float64 { return &value }
+	count := func(value int64) *int64 { return &value }
+
+	tests := []struct {
+		name  string
+		query string
+		want  SearchQuery
+	}{
+		{
+			name:  "free text",
+			query: `voilà mcp:true "travel agent"`,
+			want:  SearchQuery{Params: types.SearchParams{Name: "voilà travel agent", MCP: &yes}},
+		},
+		{
+			name:  "name",
+			query: `name:"travel agent" mcp:true`,
+			want:  SearchQuery{Params: types.SearchParams{Name: "travel agent", MCP: &yes}},
+		},
+		{
+			name:  "booleans and lists",
+			query: "mcp:true skill:a,b skill:c chain:84532",
+			want: SearchQuery{Params: types.SearchParams{
+				MCP:        &yes,
+				OASFSkills: []string{"a", "b", "c"},
+				Chains:     []types.ChainID{84532},
+			}},
+		},
+		{
+			name:  "inclusive score bounds",
+			query: "score>=0 score<=100",
+			want:  SearchQuery{Params: types.SearchParams{MinAverageScore: score(0), MaxAverageScore: score(100)}},
+		},
+		{
+			name:  "exact score",
+			query: "score=0",
+			want:  SearchQuery{Params: types.SearchParams{MinAverageScore: score(0), MaxAverageScore: score(0)}},
+		},
+		{
+			name:  "feedback count",
+			query: "feedback>0",
+			want:  SearchQuery{Params: types.SearchParams{MinFeedbackCount: count(1)}},
+		},
+		{
+			name:  "validation bounds",
+			query: "validations>=0 validationScore>=75",
+			want: SearchQuery{Params: types.SearchParams{
+				MinCompletedValidations:   count(0),
+				MinAverageValidationScore: score(75),
+			}},
+		},
+		{
+			name:  "sort",
+			query: "sort:name sort:averageScore:asc",
+			want:  SearchQuery{Sort: []string{"name:asc", "averageScore:asc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q) error: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryStrictScores(t *testing.T) {
+	got, err := ParseSearchQuery("score>80 score<90")
+	if err != nil {
+		t.Fatalf("ParseSearchQuery error: %v", err)
+	}
+
+	params := got.Params
+	if params.MinAverageScore == nil || params.MaxAverageScore == nil {
+		t.Fatalf("score bounds not set: %+v", params)
+	}
+	if *params.MinAverageScore <= 80 || *params.MinAverageScore > 80.5 {
+		t.Errorf("MinAverageScore = %v, want just above 80", *params.MinAverageScore)
+	}
+	if *params.MaxAverageScore >= 90 || *params.MaxAverageScore < 89.5 {
+		t.Errorf("MaxAverageScore = %v, want just below 90", *params.MaxAverageScore)
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		position int
+	}{
+		{name: "unknown filter", query: "agent foo:bar", position: 6},
+		{name: "free text before name", query: "voilà name:café", position: 7},
+		{name: "free text after name", query: "name:café agent", position: 11},
+		{name: "missing value", query: "mcp:", position: 0},
+		{name: "invalid boolean", query: "mcp:maybe", position: 0},
+		{name: "invalid address", query: "owner:0x123", position: 0},
+		{name: "score out of range", query: "score>101", position: 0},
+		{name: "negative upper bound", query: "score<0", position: 0},
+		{name: "feedback upper bound", query: "feedback<=3", position: 0},
+		{name: "validation score upper bound", query: "validationScore<50", position: 0},
+		{name: "unknown sort field", query: "sort:price", position: 0},
+		{name: "position after multi-byte runes", query: "voilà mcp:maybe", position: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.query)
+
+			var queryErr *SearchQueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseSearchQuery(%q): expected SearchQueryError, got %v", tt.query, err)
+			}
+			if queryErr.Position != tt.position {
+				t.Errorf("ParseSearchQuery(%q) position = %d, want %d", tt.query, queryErr.Position, tt.position)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// Signer signs transactions, hashes and typed data on behalf of a single account.
+//
+// Signatures are returned in the 65-byte [R || S || V] format with V set to 27 or 28,
+// which is the format expected by Solidity signature verification.
+type Signer interface {
+	// Address returns the address of the signing account.
+	Address() common.Address
+
+	// SignTx signs a transaction for the given chain ID.
+	SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+
+	// SignHash signs a 32-byte hash as is (no message prefix is added).
+	SignHash(hash []byte) ([]byte, error)
+
+	// SignTypedData signs EIP-712 typed data.
+	SignTypedData(typedData apitypes.TypedData) ([]byte, error)
+}
+
+// TextSigner is implemented by signers that sign EIP-191 personal messages natively.
+// Signers that cannot sign raw hashes (e.g. Clef) must implement it.
+type TextSigner interface {
+	// SignText signs the message with the "\x19Ethereum Signed Message:\n" prefix.
+	SignText(message []byte) ([]byte, error)
+}
+
+// ErrSignHashUnsupported is returned by signers that cannot sign raw hashes.
+var ErrSignHashUnsupported = errors.New("signer does not support signing raw hashes")
+
+// SignText signs an EIP-191 personal message with the signer, using the native
+// implementation of the signer when available.
+func SignText(signer Signer, message []byte) ([]byte, error) {
+	if textSigner, ok := signer.(TextSigner); ok {
+		return textSigner.SignText(message)
+	}
+	return signer.SignHash(accounts.TextHash(message))
+}
+
+// PrivateKeySigner is a signer backed by an in-memory private key.
+type PrivateKeySigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+// NewPrivateKeySigner creates a new signer from a hex encoded private key.
+func NewPrivateKeySigner(hexKey string) (*PrivateKeySigner, error) {
+	trimmedKey := strings.TrimPrefix(strings.TrimSpace(hexKey), "0x")
+	if trimmedKey == "" {
+		return nil, errors.New("private key cannot be empty")
+	}
+
+	privateKey, err := crypto.HexToECDSA(trimmedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return NewPrivateKeySignerFromECDSA(privateKey), nil
+}
+
+// NewPrivateKeySignerFromECDSA creates a new signer from a private key.
+func NewPrivateKeySignerFromECDSA(privateKey *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
+
+// Address returns the address of the signing account.
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs a transaction for the given chain ID.
+func (s *PrivateKeySigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	return ethtypes.SignTx(tx, ethtypes.LatestSignerForChainID(chainID), s.privateKey)
+}
+
+// SignHash signs a 32-byte hash as is (no message prefix is added).
+func (s *PrivateKeySigner) SignHash(hash []byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash, s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// SignTypedData signs EIP-712 typed data.
+func (s *PrivateKeySigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return s.SignHash(hash)
+}
+
+// NewKeystoreSigner creates a new signer from an encrypted geth keystore file.
+// The key is decrypted once and kept in memory.
+func NewKeystoreSigner(path, passphrase string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore file: %w", err)
+	}
+
+	return NewPrivateKeySignerFromECDSA(key.PrivateKey), nil
+}
+
+// ExternalSigner is a signer that delegates to an external signer over JSON-RPC (Clef compatible).
+// The external signer may prompt for approval, so requests use the EXTERNAL_SIGNER timeout.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewExternalSigner connects to an external signer (IPC socket path or HTTP URL) and
+// uses the given account, or the first account of the signer when the address is empty.
+func NewExternalSigner(endpoint string, address string) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to external signer: %w", err)
+	}
+
+	signer := &ExternalSigner{client: client}
+
+	if address != "" {
+		if !common.IsHexAddress(address) {
+			client.Close()
+			return nil, fmt.Errorf("invalid signer address: %s", address)
+		}
+		signer.address = common.HexToAddress(address)
+		return signer, nil
+	}
+
+	var addresses []common.Address
+	if err := signer.call(&addresses, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list external signer accounts: %w", err)
+	}
+	if len(addresses) == 0 {
+		client.Close()
+		return nil, errors.New("external signer has no accounts")
+	}
+	signer.address = addresses[0]
+
+	return signer, nil
+}
+
+// Close closes the connection to the external signer.
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
+
+// Address returns the address of the signing account.
+func (s *ExternalSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs a transaction for the given chain ID.
+func (s *ExternalSigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	if tx.Type() == ethtypes.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	}
+
+	var response struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := s.call(&response, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("external signer failed to sign transaction: %w", err)
+	}
+
+	signedTx := new(ethtypes.Transaction)
+	if err := signedTx.UnmarshalBinary(response.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignHash is not supported by Clef, which only signs structured content.
+func (s *ExternalSigner) SignHash(hash []byte) ([]byte, error) {
+	return nil, ErrSignHashUnsupported
+}
+
+// SignText signs the message with the "\x19Ethereum Signed Message:\n" prefix.
+func (s *ExternalSigner) SignText(message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := s.call(&signature, "account_signData",
+		accounts.MimetypeTextPlain,
+		common.NewMixedcaseAddress(s.address),
+		hexutil.Encode(message),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("external signer failed to sign message: %w", err)
+	}
+	return signature, nil
+}
+
+// SignTypedData signs EIP-712 typed data.
+func (s *ExternalSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := s.call(&signature, "account_signTypedData", common.NewMixedcaseAddress(s.address), typedData)
+	if err != nil {
+		return nil, fmt.Errorf("external signer failed to sign typed data: %w", err)
+	}
+	return signature, nil
+}
+
+// call sends a request to the external signer.
+func (s *ExternalSigner) call(result any, method string, args ...any) error {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(utils.TIMEOUTS["EXTERNAL_SIGNER"])*time.Millisecond,
+	)
+	defer cancel()
+
+	return s.client.CallContext(ctx, result, method, args...)
+}
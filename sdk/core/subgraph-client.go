@@ -2,10 +2,13 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	graphql "github.com/hasura/go-graphql-client"
@@ -27,19 +30,20 @@ type SubgraphQueryOptions struct {
 // QueryAgent is the query agent response from a subgraph query.
 type QueryAgent struct {
 	ID               string                      `json:"id"`
-	ChainID          types.ChainID               `json:"chainId"`
+	ChainID          types.ChainID               `json:"chainId,string"`
 	AgentID          types.AgentID               `json:"agentId"`
 	Owner            types.Address               `json:"owner"`
 	Operators        []types.Address             `json:"operators"`
 	AgentURI         types.URI                   `json:"agentUri"`
-	CreatedAt        int64                       `json:"createdAt"`
-	UpdatedAt        int64                       `json:"updatedAt"`
+	CreatedAt        int64                       `json:"createdAt,string"`
+	UpdatedAt        int64                       `json:"updatedAt,string"`
 	RegistrationFile model.AgentRegistrationFile `json:"registrationFile"`
 }
 
 // SubgraphClient is a client for querying the subgraph.
 type SubgraphClient struct {
-	client *graphql.Client
+	client            *graphql.Client
+	registrationFiles *RegistrationFileReader // OASF skills and domains, not indexed by the subgraphs
 }
 
 // NewSubgraphClient creates a new subgraph client.
@@ -51,17 +55,29 @@ func NewSubgraphClient(subgraphURL string) *SubgraphClient {
 	})
 
 	return &SubgraphClient{
-		client: client,
+		client:            client,
+		registrationFiles: NewRegistrationFileReader(),
 	}
 }
 
-// Query queries the subgraph with a given query and variables.
-func (c *SubgraphClient) Query(query string, variables map[string]any) any {
-	return c.client.Query(context.Background(), query, variables)
+// Query queries the subgraph with a given query and variables and returns the data of
+// the response.
+func (c *SubgraphClient) Query(query string, variables map[string]any) (map[string]any, error) {
+	raw, err := c.client.ExecRaw(context.Background(), query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subgraph: %w", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse subgraph response: %w", err)
+	}
+
+	return data, nil
 }
 
 // GetAgents queries the subgraph for agents with the given options.
-func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSummary {
+func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) ([]types.AgentSummary, error) {
 	if options.Where == nil {
 		options.Where = make(map[string]any)
 	}
@@ -75,7 +91,8 @@ func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSu
 		options.OrderDirection = ORDER_DIRECTION_DESC
 	}
 	if options.IncludeRegistrationFile == nil {
-		*options.IncludeRegistrationFile = true
+		includeRegistrationFile := true
+		options.IncludeRegistrationFile = &includeRegistrationFile
 	}
 
 	// Support Agent-level filters and nested registration file filters
@@ -120,7 +137,7 @@ func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSu
 					if b, ok := nv.(bool); ok {
 						nestedConditions = append(nestedConditions, fmt.Sprintf("%s: %v", nk, b))
 					} else if s, ok := nv.(string); ok {
-						nestedConditions = append(nestedConditions, fmt.Sprintf("%s: %s", nk, s))
+						nestedConditions = append(nestedConditions, fmt.Sprintf("%s: %q", nk, s))
 					} else if nv == nil {
 						if strings.HasSuffix(nk, "_not") {
 							nestedConditions = append(nestedConditions, fmt.Sprintf("%s: null", nk))
@@ -138,9 +155,12 @@ func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSu
 			} else if b, ok := v.(bool); ok {
 				conditions = append(conditions, fmt.Sprintf("%s: %v", k, b))
 			} else if s, ok := v.(string); ok {
-				conditions = append(conditions, fmt.Sprintf("%s: %s", k, s))
+				conditions = append(conditions, fmt.Sprintf("%s: %q", k, s))
 			} else if i, ok := v.(int); ok {
 				conditions = append(conditions, fmt.Sprintf("%s: %d", k, i))
+			} else if a, ok := v.([]string); ok {
+				list, _ := json.Marshal(a)
+				conditions = append(conditions, fmt.Sprintf("%s: %s", k, list))
 			} else if a, ok := v.([]any); ok {
 				conditions = append(conditions, fmt.Sprintf("%s: %v", k, a))
 			} else if v == nil {
@@ -152,37 +172,18 @@ func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSu
 			}
 		}
 		if len(conditions) > 0 {
-			whereClause = fmt.Sprintf("where: { \"%s\" }", strings.Join(conditions, ", "))
+			whereClause = fmt.Sprintf("where: { %s }", strings.Join(conditions, ", "))
 		}
 	}
 
 	// Build registration file fragment
 	regFileFragment := ""
 	if *options.IncludeRegistrationFile {
-		regFileFragment = `
+		regFileFragment = fmt.Sprintf(`
 			registrationFile {
-				id
-				agentId
-				name
-				description
-				image
-				active
-				x402support
-				supportedTrusts
-				mcpEndpoint
-				mcpVersion
-				a2aEndpoint
-				a2aVersion
-				ens
-				did
-				agentWallet
-				agentWalletChainId
-				mcpTools
-				mcpPrompts
-				mcpResources
-				a2aSkills
+				%s
 			}
-		`
+		`, c.registrationFileFields())
 	}
 
 	query := fmt.Sprintf(`
@@ -214,27 +215,43 @@ func (c *SubgraphClient) GetAgents(options SubgraphQueryOptions) []types.AgentSu
 		"orderDirection": options.OrderDirection,
 	}
 
-	data := c.Query(query, variables)
+	data, err := c.Query(query, variables)
+	if err != nil {
+		return nil, err
+	}
 
-	if data, ok := data.(map[string]any); ok {
-		if agents, ok := data["agents"].([]any); ok {
-			agentSummaries := make([]types.AgentSummary, 0, len(agents))
-			for _, agent := range agents {
-				agentSummary := c.transformAgent(agent.(QueryAgent))
-				agentSummaries = append(agentSummaries, agentSummary)
-			}
-			return agentSummaries
+	agents, ok := data["agents"].([]any)
+	if !ok {
+		return nil, errors.New("failed to get agents from subgraph: no agents in response")
+	}
+	agentSummaries := make([]types.AgentSummary, 0, len(agents))
+	for _, agent := range agents {
+		queryAgent, ok := decodeQueryAgent(agent)
+		if !ok {
+			continue
 		}
+		agentSummaries = append(agentSummaries, c.transformAgent(queryAgent))
 	}
-
-	log.Fatal("Failed to get agents from subgraph")
-
-	return []types.AgentSummary{}
+	return agentSummaries, nil
 }
 
 // GetAgentByID queries the subgraph for a single agent by ID.
 func (c *SubgraphClient) GetAgentByID(agentID types.AgentID) types.AgentSummary {
-	query := `
+	agent, found, err := c.FindAgentByID(agentID)
+	if err != nil {
+		log.Fatalf("Failed to get agent from subgraph: %v", err)
+	}
+	if !found {
+		log.Fatal("Failed to get agent from subgraph")
+	}
+
+	return agent
+}
+
+// FindAgentByID queries the subgraph for a single agent by ID and reports whether it was found.
+// Agents may not be found when the subgraph has not indexed them yet.
+func (c *SubgraphClient) FindAgentByID(agentID types.AgentID) (types.AgentSummary, bool, error) {
+	query := fmt.Sprintf(`
 		query GetAgent($agentId: String!) {
 			agent(id: $agentId) {
 				id
@@ -246,41 +263,31 @@ func (c *SubgraphClient) GetAgentByID(agentID types.AgentID) types.AgentSummary
 				createdAt
 				updatedAt
 				registrationFile {
-					id
-					agentId
-					name
-					description
-					image
-					active
-					x402support
-					supportedTrusts
-					mcpEndpoint
-					mcpVersion
-					a2aEndpoint
-					a2aVersion
-					ens
-					did
-					agentWallet
-					agentWalletChainId
-					mcpTools
-					mcpPrompts
-					mcpResources
-					a2aSkills
+					%s
 				}
 			}
 		}
-	`
+	`, c.registrationFileFields())
 
-	data := c.Query(query, map[string]any{"agentId": agentID})
-	if d, ok := data.(map[string]any); ok {
-		if a, ok := d["agent"].(QueryAgent); ok {
-			return c.transformAgent(a)
-		}
+	data, err := c.Query(query, map[string]any{"agentId": agentID})
+	if err != nil {
+		return types.AgentSummary{}, false, err
+	}
+	if agent, ok := decodeQueryAgent(data["agent"]); ok {
+		return c.transformAgent(agent), true, nil
 	}
 
-	log.Fatal("Failed to get agent from subgraph")
+	return types.AgentSummary{}, false, nil
+}
 
-	return types.AgentSummary{}
+// registrationFileFields returns the registration file fields selected by agent queries.
+func (c *SubgraphClient) registrationFileFields() string {
+	fields := []string{
+		"id", "agentId", "name", "description", "image", "active", "x402support", "supportedTrusts",
+		"mcpEndpoint", "mcpVersion", "a2aEndpoint", "a2aVersion", "ens", "did", "agentWallet",
+		"agentWalletChainId", "mcpTools", "mcpPrompts", "mcpResources", "a2aSkills",
+	}
+	return strings.Join(fields, "\n")
 }
 
 // transformAgent transforms the raw subgraph agent into an agent summary.
@@ -288,29 +295,105 @@ func (c *SubgraphClient) transformAgent(agent QueryAgent) types.AgentSummary {
 	return types.AgentSummary{
 		ChainID:         agent.ChainID,
 		AgentID:         agent.AgentID,
-		Name:            *agent.RegistrationFile.Name,
-		Description:     *agent.RegistrationFile.Description,
-		Image:           *agent.RegistrationFile.Image,
+		Name:            valueOrZero(agent.RegistrationFile.Name),
+		Description:     valueOrZero(agent.RegistrationFile.Description),
+		Image:           valueOrZero(agent.RegistrationFile.Image),
 		Owners:          []types.Address{agent.Owner},
 		Operators:       agent.Operators,
-		MCP:             *agent.RegistrationFile.McpEndpoint != "",
-		A2A:             *agent.RegistrationFile.A2aEndpoint != "",
-		ENS:             *agent.RegistrationFile.Ens,
-		DID:             *agent.RegistrationFile.Did,
-		WalletAddress:   *agent.RegistrationFile.AgentWallet,
+		MCP:             valueOrZero(agent.RegistrationFile.McpEndpoint) != "",
+		A2A:             valueOrZero(agent.RegistrationFile.A2aEndpoint) != "",
+		ENS:             valueOrZero(agent.RegistrationFile.Ens),
+		DID:             valueOrZero(agent.RegistrationFile.Did),
+		WalletAddress:   types.Address(valueOrZero(agent.RegistrationFile.AgentWallet)),
 		SupportedTrusts: agent.RegistrationFile.SupportedTrusts,
 		A2ASkills:       agent.RegistrationFile.A2aSkills,
 		MCPTools:        agent.RegistrationFile.McpTools,
 		MCPPrompts:      agent.RegistrationFile.McpPrompts,
 		MCPResources:    agent.RegistrationFile.McpResources,
-		Active:          *agent.RegistrationFile.Active,
-		X402Support:     *agent.RegistrationFile.X402support,
+		OASFSkills:      []string{},
+		OASFDomains:     []string{},
+		Active:          valueOrZero(agent.RegistrationFile.Active),
+		X402Support:     valueOrZero(agent.RegistrationFile.X402support),
 		Extras:          map[string]any{},
+		Registrations:   []string{},
+		AgentURI:        agent.AgentURI,
+	}
+}
+
+// GetAgentStats queries the subgraph for the reputation statistics of the given agents.
+// The agent IDs must be in the format "chainID:tokenID" and are queried in bulk.
+func (c *SubgraphClient) GetAgentStats(agentIDs []types.AgentID) (map[types.AgentID]types.AgentReputation, error) {
+	stats := make(map[types.AgentID]types.AgentReputation, len(agentIDs))
+
+	// Query in batches to stay within the subgraph "first" limit
+	batchSize := 1000
+	for start := 0; start < len(agentIDs); start += batchSize {
+		end := min(start+batchSize, len(agentIDs))
+
+		quotedIDs := make([]string, 0, end-start)
+		for _, agentID := range agentIDs[start:end] {
+			quotedIDs = append(quotedIDs, fmt.Sprintf("\"%s\"", agentID))
+		}
+
+		query := fmt.Sprintf(`
+			{
+				agentStats(
+					where: { id_in: [%s] }
+					first: %d
+				) {
+					id
+					totalFeedback
+					averageScore
+					scoreDistribution
+					totalValidations
+					completedValidations
+					averageValidationScore
+				}
+			}
+		`, strings.Join(quotedIDs, ", "), end-start)
+
+		data, err := c.Query(query, map[string]any{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent stats from subgraph: %w", err)
+		}
+
+		agentStats, _ := data["agentStats"].([]any)
+		for _, rawStats := range agentStats {
+			s, ok := rawStats.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			id, _ := s["id"].(string)
+
+			distribution := []int64{}
+			if buckets, ok := s["scoreDistribution"].([]any); ok {
+				for _, bucket := range buckets {
+					distribution = append(distribution, parseSubgraphInt(bucket))
+				}
+			}
+
+			stats[id] = types.AgentReputation{
+				FeedbackCount:     parseSubgraphInt(s["totalFeedback"]),
+				AverageScore:      parseSubgraphDecimal(s["averageScore"]),
+				ScoreDistribution: distribution,
+				Validations: types.ValidationSummary{
+					Total:        parseSubgraphInt(s["totalValidations"]),
+					Completed:    parseSubgraphInt(s["completedValidations"]),
+					AverageScore: parseSubgraphDecimal(s["averageValidationScore"]),
+				},
+			}
+		}
 	}
+
+	return stats, nil
 }
 
-// SearchAgents searches the subgraph for agents with the given parameters.
-func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int64) []types.AgentSummary {
+// SearchAgents searches the subgraph for agents with the given parameters. Filters the
+// subgraph does not support are applied to the fetched page, so the number of agents
+// fetched before filtering (fetched) is returned for pagination: the last page is the
+// first page with fewer than first agents fetched.
+func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int64) (agents []types.AgentSummary, fetched int64, err error) {
 	if first == 0 {
 		first = 100
 	}
@@ -323,7 +406,8 @@ func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int
 	// For now, we'll do basic filtering on Agent fields and then filter on registrationFile fields
 	if params.Active != nil || params.MCP != nil || params.A2A != nil || params.X402Support != nil ||
 		params.ENS != "" || params.WalletAddress != "" || params.SupportedTrust != nil || params.A2ASkills != nil ||
-		params.MCPTools != nil || params.Name != "" || params.Owners != nil || params.Operators != nil {
+		params.MCPTools != nil || params.Name != "" || params.Owners != nil || params.Operators != nil ||
+		params.OASFSkills != nil || params.OASFDomains != nil {
 		// Push basic filters to subgraph using nested registrationFile filters
 		registrationFileFilters := map[string]any{}
 		if params.Active != nil {
@@ -336,7 +420,7 @@ func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int
 			registrationFileFilters["ens"] = strings.ToLower(params.ENS)
 		}
 		if params.WalletAddress != "" {
-			registrationFileFilters["agentWallet"] = strings.ToLower(params.WalletAddress)
+			registrationFileFilters["agentWallet"] = params.WalletAddress.Lower()
 		}
 		if params.MCP != nil {
 			if *params.MCP {
@@ -362,10 +446,7 @@ func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int
 		// Owner filtering (at Agent level, not registrationFile)
 		if len(params.Owners) > 0 {
 			// Normalize addresses to lowercase for case-insensitive matching
-			normalizedOwners := make([]string, len(params.Owners))
-			for i, owner := range params.Owners {
-				normalizedOwners[i] = strings.ToLower(string(owner))
-			}
+			normalizedOwners := types.LowerAddresses(params.Owners)
 			if len(normalizedOwners) == 1 {
 				whereWithFilters["owner"] = normalizedOwners[0]
 			} else {
@@ -376,20 +457,38 @@ func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int
 		// Operator filtering (at Agent level, not registrationFile)
 		if len(params.Operators) > 0 {
 			// Normalize addresses to lowercase for case-insensitive matching
-			normalizedOperators := make([]string, len(params.Operators))
-			for i, operator := range params.Operators {
-				normalizedOperators[i] = strings.ToLower(string(operator))
-			}
+			normalizedOperators := types.LowerAddresses(params.Operators)
 			// For operators (array field), use contains to check if any operator matches
 			whereWithFilters["operators_contains"] = normalizedOperators
 		}
 
+		// Expand OASF slugs once using the taxonomy (hierarchical matching)
+		expandedSkills := []string{}
+		for _, skill := range params.OASFSkills {
+			expandedSkills = append(expandedSkills, ExpandSkill(skill)...)
+		}
+		expandedDomains := []string{}
+		for _, domain := range params.OASFDomains {
+			expandedDomains = append(expandedDomains, ExpandDomain(domain)...)
+		}
+
 		// Fetch records with filters and pagination applied at subgraph level
-		allAgents := c.GetAgents(SubgraphQueryOptions{
+		allAgents, err := c.GetAgents(SubgraphQueryOptions{
 			Where: whereWithFilters,
 			First: first,
 			Skip:  skip,
 		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// The subgraphs do not index OASF skills and domains, so they are read from the
+		// registration files (agents whose file cannot be read match no OASF filter)
+		if hasOASFFilters(params) {
+			if err := c.registrationFiles.Fill(allAgents); err != nil {
+				log.Printf("warning: failed to read registration files for OASF filters: %v", err)
+			}
+		}
 
 		// Only filter client-side for fields that can't be filtered at subgraph level
 		// Fields already filtered at subgraph level: active, x402support, mcp, a2a, ens, walletAddress, owners, operators
@@ -436,16 +535,23 @@ func (c *SubgraphClient) SearchAgents(params types.SearchParams, first, skip int
 					continue
 				}
 			}
+			if len(params.OASFSkills) > 0 && !MatchOASFSlugs(agent.OASFSkills, params.OASFSkills, expandedSkills) {
+				continue
+			}
+			if len(params.OASFDomains) > 0 && !MatchOASFSlugs(agent.OASFDomains, params.OASFDomains, expandedDomains) {
+				continue
+			}
 			filteredAgents = append(filteredAgents, agent)
 		}
-		return filteredAgents
+		return filteredAgents, int64(len(allAgents)), nil
 	}
 
-	return c.GetAgents(SubgraphQueryOptions{
+	agents, err = c.GetAgents(SubgraphQueryOptions{
 		Where: where,
 		First: first,
 		Skip:  skip,
 	})
+	return agents, int64(len(agents)), err
 }
 
 // SearchFeedback searches the subgraph for feedback with the given parameters.
@@ -455,7 +561,7 @@ func (c *SubgraphClient) SearchFeedback(
 	skip int64,
 	orderBy string,
 	orderDirection OrderDirection,
-) []any {
+) ([]any, error) {
 	if first == 0 {
 		first = 100
 	}
@@ -479,9 +585,10 @@ func (c *SubgraphClient) SearchFeedback(
 	}
 
 	if len(params.Reviewers) > 0 {
+		// The subgraph stores lowercase addresses
 		reviewerAddresses := make([]string, len(params.Reviewers))
 		for i, reviewer := range params.Reviewers {
-			reviewerAddresses[i] = string(reviewer)
+			reviewerAddresses[i] = strconv.Quote(reviewer.Lower())
 		}
 		reviewerAddressesString := strings.Join(reviewerAddresses, ", ")
 		whereConditions = append(whereConditions, fmt.Sprintf("clientAddress_in: [%s]", reviewerAddressesString))
@@ -602,8 +709,12 @@ func (c *SubgraphClient) SearchFeedback(
 		}
 	  }`, whereClause, first, skip, orderBy, orderDirection)
 
-	result := c.Query(query, map[string]any{})
-	return result.(map[string]any)["feedbacks"].([]any)
+	data, err := c.Query(query, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	feedbacks, _ := data["feedbacks"].([]any)
+	return feedbacks, nil
 }
 
 // SearchAgentsByReputation searches the subgraph for agents by reputation with the given parameters.
@@ -621,7 +732,7 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 	skip int64,
 	orderBy string,
 	orderDirection OrderDirection,
-) []SearchAgentsByReputationResult {
+) ([]SearchAgentsByReputationResult, error) {
 	if first == 0 {
 		first = 100
 	}
@@ -700,8 +811,11 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 			}
 		`, feedbackWhere)
 
-		feedbackResult := c.Query(feedbackQuery, map[string]any{})
-		feedbackData := feedbackResult.(map[string]any)["feedbacks"].([]any)
+		feedbackResult, err := c.Query(feedbackQuery, map[string]any{})
+		if err != nil {
+			return nil, err
+		}
+		feedbackData, _ := feedbackResult["feedbacks"].([]any)
 
 		// Extract unique agnet IDs
 		agentIDsSet := make(map[string]bool)
@@ -717,7 +831,7 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 
 		if len(agentIDsSet) == 0 {
 			// No agents have matching feedback
-			return []SearchAgentsByReputationResult{}
+			return []SearchAgentsByReputationResult{}, nil
 		}
 
 		// Apply agent filter if specified
@@ -730,7 +844,7 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 			}
 			if len(agentIDsList) == 0 {
 				// If feedback query fails, return empty
-				return []SearchAgentsByReputationResult{}
+				return []SearchAgentsByReputationResult{}, nil
 			}
 		}
 
@@ -827,20 +941,31 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 		}
 	`, agentWhere, first, skip, orderBy, orderDirection, feedbackWhereForAgents)
 
-	result := c.Query(query, map[string]any{})
-	agentsResult := result.(map[string]any)["agents"].([]any)
+	result, err := c.Query(query, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	agentsResult, _ := result["agents"].([]any)
 
 	// Calculate agerage scores
 	agentsWithScores := []SearchAgentsByReputationResult{}
 	for _, agent := range agentsResult {
-		feedbacks := []map[string]any{}
+		raw, ok := agent.(map[string]any)
+		if !ok {
+			continue
+		}
+		feedbacks, _ := raw["feedback"].([]any)
 		averageScore := new(int64)
 
 		if len(feedbacks) > 0 {
 			scores := []int64{}
 			for _, feedback := range feedbacks {
-				if score, ok := feedback["score"].(int64); ok && score > 0 {
-					scores = append(scores, feedback["score"].(int64))
+				fields, ok := feedback.(map[string]any)
+				if !ok {
+					continue
+				}
+				if score := parseSubgraphInt(fields["score"]); score > 0 {
+					scores = append(scores, score)
 				}
 			}
 			if len(scores) > 0 {
@@ -852,9 +977,13 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 		}
 
 		// Remove feedback array from result (not part of QueryAgent)
-		delete(agent.(map[string]any), "feedback")
+		delete(raw, "feedback")
+		queryAgent, ok := decodeQueryAgent(raw)
+		if !ok {
+			continue
+		}
 		agentsWithScores = append(agentsWithScores, SearchAgentsByReputationResult{
-			QueryAgent:   agent.(QueryAgent),
+			QueryAgent:   queryAgent,
 			AverageScore: averageScore,
 		})
 	}
@@ -870,7 +999,68 @@ func (c *SubgraphClient) SearchAgentsByReputation(
 		}
 	}
 
-	return filteredAgents
+	return filteredAgents, nil
+}
+
+// decodeQueryAgent decodes a raw subgraph agent (as returned by Query) into a query agent.
+func decodeQueryAgent(raw any) (QueryAgent, bool) {
+	if raw == nil {
+		return QueryAgent{}, false
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return QueryAgent{}, false
+	}
+
+	var agent QueryAgent
+	if err := json.Unmarshal(data, &agent); err != nil {
+		log.Printf("Failed to decode subgraph agent: %v", err)
+		return QueryAgent{}, false
+	}
+
+	return agent, true
+}
+
+// valueOrZero returns the value of an optional subgraph field or the zero value if unset.
+func valueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
+// parseSubgraphInt parses a subgraph BigInt (string) or Int (number) value.
+func parseSubgraphInt(value any) int64 {
+	switch v := value.(type) {
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return i
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// parseSubgraphDecimal parses a subgraph BigDecimal (string) or numeric value.
+func parseSubgraphDecimal(value any) float64 {
+	switch v := value.(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0
+		}
+		return f
+	case float64:
+		return v
+	default:
+		return 0
+	}
 }
 
 // ...
@@ -884,7 +1074,7 @@ const (
 
 type SearchFeedbackParams struct {
 	Agents         []string
-	Reviewers      []string
+	Reviewers      []types.Address
 	Tags           []string
 	Capabilities   []string
 	Skills         []string
@@ -0,0 +1,412 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// SAFE_TX_BUILDER_VERSION is the Safe Transaction Builder version of exported batches.
+const SAFE_TX_BUILDER_VERSION = "1.18.0"
+
+// ErrUnsignedTransaction is returned when sending a raw transaction without signature.
+var ErrUnsignedTransaction = errors.New("transaction is not signed")
+
+// ErrMixedChainBatch is returned when a Safe transaction batch mixes transactions of
+// several chains.
+var ErrMixedChainBatch = errors.New("safe transaction batch mixes chains")
+
+// TransactionBuilder builds the transactions of the SDK write operations without signing
+// or sending them, for offline or multisig (e.g. Safe) signing. Signed transactions are
+// sent with SDK.SendRawTransaction.
+type TransactionBuilder struct {
+	sdk *SDK
+}
+
+// TransactionBuilder returns the transaction builder of the SDK.
+func (s *SDK) TransactionBuilder() *TransactionBuilder {
+	return &TransactionBuilder{sdk: s}
+}
+
+// Register builds the registration of a new agent. The agent URI is empty for the IPFS
+// workflow (upload the registration file with Agent.UploadIPFS once the agent ID is known).
+func (b *TransactionBuilder) Register(agent *Agent, agentURI types.URI) UnsignedTransaction {
+	return b.build(b.sdk.registerCall(agent, agentURI))
+}
+
+// SetAgentURI builds setting the agent URI.
+func (b *TransactionBuilder) SetAgentURI(agentID types.AgentID, agentURI types.URI) UnsignedTransaction {
+	return b.build(b.sdk.setAgentURICall(agentID, agentURI))
+}
+
+// SetMetadata builds setting an on-chain metadata entry of the agent.
+func (b *TransactionBuilder) SetMetadata(agentID types.AgentID, key string, value any) UnsignedTransaction {
+	return b.build(b.sdk.setMetadataCall(agentID, key, value))
+}
+
+// GiveFeedback builds giving feedback (the feedback file is uploaded to IPFS when available).
+func (b *TransactionBuilder) GiveFeedback(agentID types.AgentID, feedbackFile map[string]any, feedbackAuth string) UnsignedTransaction {
+	return b.build(b.sdk.giveFeedbackCall(agentID, feedbackFile, feedbackAuth, true))
+}
+
+// AppendResponse builds appending a response to feedback (sent by the agent owner or an
+// operator).
+func (b *TransactionBuilder) AppendResponse(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+	response FeedbackResponse,
+) UnsignedTransaction {
+	return b.build(b.sdk.appendResponseCall(agentID, clientAddress, feedbackIndex, response))
+}
+
+// RevokeFeedback builds revoking feedback.
+func (b *TransactionBuilder) RevokeFeedback(agentID types.AgentID, feedbackIndex int64) UnsignedTransaction {
+	return b.build(b.sdk.revokeFeedbackCall(agentID, feedbackIndex))
+}
+
+// Transfer builds transferring the agent ownership (sent by the current owner).
+func (b *TransactionBuilder) Transfer(agentID types.AgentID, newOwner types.Address) UnsignedTransaction {
+	return b.build(b.sdk.transferCall(agentID, newOwner))
+}
+
+// Approve builds approving the operator of the agent (zero address to revoke it).
+func (b *TransactionBuilder) Approve(agentID types.AgentID, operator types.Address) UnsignedTransaction {
+	return b.build(b.sdk.approveCall(agentID, operator))
+}
+
+// SetApprovalForAll builds approving (or removing) an operator of all agents of the sender.
+func (b *TransactionBuilder) SetApprovalForAll(operator types.Address, approved bool) UnsignedTransaction {
+	return b.build(b.sdk.setApprovalForAllCall(operator, approved))
+}
+
+// build builds an unsigned contract transaction.
+func (b *TransactionBuilder) build(call contractCall) UnsignedTransaction {
+	return b.sdk.web3Client.buildTransaction(call.contract, call.method, call.data)
+}
+
+// BuildTransaction builds an unsigned contract transaction.
+func (c *Web3Client) BuildTransaction(contract *Contract, methodName string, args ...any) (UnsignedTransaction, error) {
+	data, err := c.packContractCall(contract, methodName, args...)
+	if err != nil {
+		return UnsignedTransaction{}, err
+	}
+
+	return c.buildTransaction(contract, methodName, data), nil
+}
+
+// buildTransaction builds an unsigned contract transaction with packed call data.
+func (c *Web3Client) buildTransaction(contract *Contract, methodName string, data []byte) UnsignedTransaction {
+	return UnsignedTransaction{
+		ChainID: c.ChainID,
+		To:      types.AddressFromCommon(contract.Address()),
+		Value:   "0",
+		Data:    hexutil.Encode(data),
+		Method:  methodName,
+	}
+}
+
+// FillTransaction completes an unsigned transaction for offline signing by the given
+// sender: nonce (pending state of the node), gas limit (estimated) and fees (fee strategy).
+func (c *Web3Client) FillTransaction(unsigned UnsignedTransaction, from types.Address) (*ethtypes.Transaction, error) {
+	ctx := context.Background()
+	sender := from.Common()
+	to := unsigned.To.Common()
+
+	data, err := hexutil.Decode(unsigned.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction data: %w", err)
+	}
+	value, ok := new(big.Int).SetString(unsigned.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction value: %s", unsigned.Value)
+	}
+
+	nonce, err := c.Provider.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	gas, err := c.Provider.EstimateGas(ctx, ethereum.CallMsg{From: sender, To: &to, Value: value, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", decodeTransactionError(err))
+	}
+
+	fees := c.transactionFees(ctx, TransactionOptions{})
+	if !fees.IsDynamic() {
+		return ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: fees.GasPrice,
+			Gas:      gas,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		}), nil
+	}
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   unsigned.ChainID.BigInt(),
+		Nonce:     nonce,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	}), nil
+}
+
+// SendRawTransaction sends an externally signed transaction (hex encoded) and returns
+// the transaction hash.
+func (c *Web3Client) SendRawTransaction(rawTx string) (string, error) {
+	encoded, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw transaction: %w", err)
+	}
+
+	tx := new(ethtypes.Transaction)
+	if err := tx.UnmarshalBinary(encoded); err != nil {
+		return "", fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	if _, r, _ := tx.RawSignatureValues(); r == nil || r.Sign() == 0 {
+		return "", ErrUnsignedTransaction
+	}
+	if tx.ChainId().Sign() > 0 && tx.ChainId().Cmp(c.ChainID.BigInt()) != 0 {
+		return "", fmt.Errorf("transaction chain ID %s does not match chain %d", tx.ChainId(), c.ChainID)
+	}
+
+	if err := c.Provider.SendTransaction(context.Background(), tx); err != nil {
+		return "", decodeTransactionError(err)
+	}
+
+	// The nonces of the SDK signer are out of date when it signed the transaction
+	if sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		c.nonces.Resync(sender)
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// SendRawTransaction sends an externally signed transaction (hex encoded) and returns
+// the transaction hash.
+func (s *SDK) SendRawTransaction(rawTx string) string {
+	txHash, err := s.web3Client.SendRawTransaction(rawTx)
+	if err != nil {
+		log.Fatalf("Failed to send raw transaction: %v", err)
+	}
+	return txHash
+}
+
+// NewSafeTransactionBatch creates a Safe Transaction Builder batch of transactions, to be
+// imported in the Safe{Wallet} Transaction Builder app. A batch is executed on one chain,
+// so all transactions must be built for the same chain.
+func NewSafeTransactionBatch(safeAddress types.Address, name string, transactions ...UnsignedTransaction) (SafeTransactionBatch, error) {
+	if len(transactions) == 0 {
+		return SafeTransactionBatch{}, errors.New("safe transaction batch is empty")
+	}
+	for _, tx := range transactions[1:] {
+		if tx.ChainID != transactions[0].ChainID {
+			return SafeTransactionBatch{}, fmt.Errorf("%w: chains %d and %d", ErrMixedChainBatch, transactions[0].ChainID, tx.ChainID)
+		}
+	}
+
+	batch := SafeTransactionBatch{
+		ChainID:   fmt.Sprint(transactions[0].ChainID),
+		Version:   "1.0",
+		CreatedAt: time.Now().UnixMilli(),
+		Meta: SafeTransactionBatchMeta{
+			Name:                   name,
+			TxBuilderVersion:       SAFE_TX_BUILDER_VERSION,
+			CreatedFromSafeAddress: safeAddress.Checksum(),
+		},
+		Transactions: []SafeTransaction{},
+	}
+
+	for _, tx := range transactions {
+		batch.Transactions = append(batch.Transactions, SafeTransaction{
+			To:    tx.To.String(),
+			Value: tx.Value,
+			Data:  tx.Data,
+		})
+	}
+
+	return batch, nil
+}
+
+// UploadIPFS uploads the registration file of a registered agent to IPFS and returns the
+// agent URI, for setting it with a transaction signed outside of the SDK.
+func (a *Agent) UploadIPFS() types.URI {
+	if a.sdk.ipfsClient == nil {
+		log.Fatal("IPFS client is required for IPFS registration")
+	}
+	if a.registrationFile.AgentID == "" {
+		log.Fatal("Agent must be registered before uploading the registration file")
+	}
+
+	cid := a.sdk.ipfsClient.AddRegistrationFile(a.registrationFile, a.sdk.ChainID(), a.sdk.registries["IDENTITY"])
+	return "ipfs://" + cid
+}
+
+// ConfirmRegistration waits for a registration transaction sent outside of the SDK (e.g.
+// by a Safe) and sets the agent ID from its receipt.
+func (a *Agent) ConfirmRegistration(txHash string) types.RegistrationFile {
+	receipt := a.sdk.web3Client.WaitForTransaction(txHash, utils.TIMEOUTS["TRANSACTION_WAIT"])
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		log.Fatalf("Registration transaction %s reverted", txHash)
+	}
+
+	tokenID := a.extractAgentIDFromReceipt(receipt)
+	a.setRegistered(tokenID.String())
+
+	return a.registrationFile
+}
+
+// Contract calls of the write operations (shared by the transaction builder, dry runs
+// and preflight checks)
+
+// registerCall returns the registration call of a new agent.
+func (s *SDK) registerCall(agent *Agent, agentURI types.URI) contractCall {
+	if agent.AgentID() != "" {
+		log.Fatalf("Agent %s is already registered", agent.AgentID())
+	}
+	return contractCall{s.GetIdentityRegistry(), "register", agent.registerData(agentURI)}
+}
+
+// setAgentURICall returns the call setting the agent URI.
+func (s *SDK) setAgentURICall(agentID types.AgentID, agentURI types.URI) contractCall {
+	tokenID := utils.ParseAgentID(agentID).TokenID
+	return contractCall{s.GetIdentityRegistry(), "setAgentUri", identityRegistryBinding.PackSetAgentUri(tokenID, agentURI)}
+}
+
+// setMetadataCall returns the call setting an on-chain metadata entry.
+func (s *SDK) setMetadataCall(agentID types.AgentID, key string, value any) contractCall {
+	tokenID := utils.ParseAgentID(agentID).TokenID
+	data := identityRegistryBinding.PackSetMetadata(tokenID, key, metadataBytes(value))
+	return contractCall{s.GetIdentityRegistry(), "setMetadata", data}
+}
+
+// giveFeedbackCall returns the call giving feedback. The feedback file is uploaded to
+// IPFS (when available) only with upload set, otherwise the feedback URI is left empty
+// (the hash still commits to the file).
+func (s *SDK) giveFeedbackCall(agentID types.AgentID, feedbackFile map[string]any, feedbackAuth string, upload bool) contractCall {
+	score, tags, err := s.feedbackManager.parseFeedbackFile(feedbackFile)
+	if err != nil {
+		log.Fatalf("Invalid feedback file: %v", err)
+	}
+
+	feedbackURI := ""
+	feedbackHash := [32]byte{}
+	if s.ipfsClient != nil {
+		feedbackJSON := s.feedbackManager.feedbackJSON(feedbackFile)
+		if upload {
+			feedbackURI = "ipfs://" + s.ipfsClient.Add(string(feedbackJSON))
+		}
+		feedbackHash = crypto.Keccak256Hash(feedbackJSON)
+	}
+
+	data, err := s.feedbackManager.giveFeedbackData(agentID, score, tags, feedbackURI, feedbackHash, feedbackAuth)
+	if err != nil {
+		log.Fatalf("Failed to build feedback: %v", err)
+	}
+	return contractCall{s.GetReputationRegistry(), "giveFeedback", data}
+}
+
+// appendResponseCall returns the call appending a response to feedback.
+func (s *SDK) appendResponseCall(
+	agentID types.AgentID,
+	clientAddress types.Address,
+	feedbackIndex int64,
+	response FeedbackResponse,
+) contractCall {
+	data := s.feedbackManager.appendResponseData(agentID, clientAddress, feedbackIndex, response.URI, response.Hash)
+	return contractCall{s.GetReputationRegistry(), "appendResponse", data}
+}
+
+// revokeFeedbackCall returns the call revoking feedback.
+func (s *SDK) revokeFeedbackCall(agentID types.AgentID, feedbackIndex int64) contractCall {
+	tokenID := utils.ParseAgentID(agentID).TokenID
+	data := reputationRegistryBinding.PackRevokeFeedback(tokenID, uint64(feedbackIndex))
+	return contractCall{s.GetReputationRegistry(), "revokeFeedback", data}
+}
+
+// transferCall returns the call transferring the agent from its current owner.
+func (s *SDK) transferCall(agentID types.AgentID, newOwner types.Address) contractCall {
+	if err := newOwner.Validate(); err != nil {
+		log.Fatalf("Invalid new owner address: %v", err)
+	}
+	owner := s.GetAgentOwner(agentID)
+	agent := newAgent(s, types.RegistrationFile{AgentID: agentID})
+	return contractCall{s.GetIdentityRegistry(), "transferFrom", agent.transferData(owner, newOwner)}
+}
+
+// approveCall returns the call approving the operator of the agent (zero address to
+// revoke it).
+func (s *SDK) approveCall(agentID types.AgentID, operator types.Address) contractCall {
+	if err := operator.Validate(); err != nil {
+		log.Fatalf("Invalid operator address: %v", err)
+	}
+	tokenID := utils.ParseAgentID(agentID).TokenID
+	return contractCall{s.GetIdentityRegistry(), "approve", identityRegistryBinding.PackApprove(operator.Common(), tokenID)}
+}
+
+// setApprovalForAllCall returns the call approving (or removing) an operator of all agents
+// of the sender.
+func (s *SDK) setApprovalForAllCall(operator types.Address, approved bool) contractCall {
+	if err := operator.Validate(); err != nil {
+		log.Fatalf("Invalid operator address: %v", err)
+	}
+	if operator.IsZero() {
+		log.Fatal("Operator cannot be the zero address")
+	}
+	data := identityRegistryBinding.PackSetApprovalForAll(operator.Common(), approved)
+	return contractCall{s.GetIdentityRegistry(), "setApprovalForAll", data}
+}
+
+// ...
+
+type contractCall struct {
+	contract *Contract
+	method   string // method name (informational, the call data is packed)
+	data     []byte
+}
+
+type UnsignedTransaction struct {
+	ChainID types.ChainID `json:"chainId"`
+	To      types.Address `json:"to"`
+	Value   string        `json:"value"` // wei (decimal)
+	Data    string        `json:"data"`  // hex encoded calldata
+	Method  string        `json:"method,omitempty"`
+}
+
+type SafeTransactionBatch struct {
+	Version      string                   `json:"version"`
+	ChainID      string                   `json:"chainId"`
+	CreatedAt    int64                    `json:"createdAt"` // milliseconds
+	Meta         SafeTransactionBatchMeta `json:"meta"`
+	Transactions []SafeTransaction        `json:"transactions"`
+}
+
+type SafeTransactionBatchMeta struct {
+	Name                    string `json:"name"`
+	Description             string `json:"description"`
+	TxBuilderVersion        string `json:"txBuilderVersion"`
+	CreatedFromSafeAddress  string `json:"createdFromSafeAddress"`
+	CreatedFromOwnerAddress string `json:"createdFromOwnerAddress"`
+}
+
+type SafeTransaction struct {
+	To                   string         `json:"to"`
+	Value                string         `json:"value"`
+	Data                 string         `json:"data"`
+	ContractMethod       any            `json:"contractMethod"`       // null with raw data
+	ContractInputsValues map[string]any `json:"contractInputsValues"` // null with raw data
+}
@@ -0,0 +1,330 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanchristo/agent0-go/sdk/types"
+)
+
+// TransactionJournal is a file-backed journal of multi-step flows (e.g. registration),
+// recording every step before and after it is executed. After a crash, incomplete flows
+// are found in the journal and resumed (or reported) instead of being started again.
+//
+// Every flow is stored in its own JSON file in the journal directory, and files are
+// replaced atomically on every change.
+type TransactionJournal struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewTransactionJournal creates a new journal in the given directory (created if missing).
+func NewTransactionJournal(dir string) (*TransactionJournal, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	return &TransactionJournal{dir: dir}, nil
+}
+
+// Begin starts a new flow of the given kind. The key identifies the work of the flow, so
+// that an incomplete flow can be found again when the same work is started after a crash.
+func (j *TransactionJournal) Begin(kind JournalFlowKind, key string, chainID types.ChainID, data map[string]any) *JournalFlow {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		log.Fatalf("Failed to generate flow ID: %v", err)
+	}
+
+	now := time.Now().Unix()
+	flow := &JournalFlow{
+		ID:        fmt.Sprintf("%d-%s", now, hex.EncodeToString(id)),
+		Kind:      kind,
+		Key:       key,
+		ChainID:   chainID,
+		Status:    JOURNAL_FLOW_STATUS_IN_PROGRESS,
+		Steps:     []JournalStep{},
+		Data:      data,
+		CreatedAt: now,
+		UpdatedAt: now,
+		journal:   j,
+	}
+	flow.persist()
+
+	return flow
+}
+
+// Find returns the most recent incomplete flow of the given kind and key.
+func (j *TransactionJournal) Find(kind JournalFlowKind, key string) (*JournalFlow, bool) {
+	flows := j.IncompleteFlows()
+	for idx := len(flows) - 1; idx >= 0; idx-- {
+		if flows[idx].Kind == kind && flows[idx].Key == key {
+			return flows[idx], true
+		}
+	}
+	return nil, false
+}
+
+// Get returns the flow with the given ID.
+func (j *TransactionJournal) Get(id string) (*JournalFlow, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	flow, err := j.read(filepath.Join(j.dir, id+".json"))
+	if err != nil {
+		return nil, false
+	}
+	return flow, true
+}
+
+// IncompleteFlows returns the flows that are still in progress, oldest first.
+func (j *TransactionJournal) IncompleteFlows() []*JournalFlow {
+	return slices.DeleteFunc(j.Flows(), func(flow *JournalFlow) bool {
+		return flow.Status != JOURNAL_FLOW_STATUS_IN_PROGRESS
+	})
+}
+
+// Flows returns all flows of the journal, oldest first.
+func (j *TransactionJournal) Flows() []*JournalFlow {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(j.dir, "*.json"))
+	if err != nil {
+		return nil
+	}
+
+	flows := []*JournalFlow{}
+	for _, path := range paths {
+		flow, err := j.read(path)
+		if err != nil {
+			continue // partially written or foreign file
+		}
+		flows = append(flows, flow)
+	}
+
+	slices.SortFunc(flows, func(a, b *JournalFlow) int {
+		if a.CreatedAt != b.CreatedAt {
+			return int(a.CreatedAt - b.CreatedAt)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return flows
+}
+
+// Remove deletes a flow from the journal.
+func (j *TransactionJournal) Remove(id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return os.Remove(filepath.Join(j.dir, id+".json"))
+}
+
+// read reads a flow file.
+func (j *TransactionJournal) read(path string) (*JournalFlow, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := &JournalFlow{}
+	if err := json.Unmarshal(content, flow); err != nil {
+		return nil, err
+	}
+	flow.journal = j
+
+	return flow, nil
+}
+
+// write writes a flow file atomically (temporary file, sync and rename).
+func (j *TransactionJournal) write(flow *JournalFlow) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	content, err := json.MarshalIndent(flow, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(j.dir, flow.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(j.dir, flow.ID+".json"))
+}
+
+// Step returns the step with the given name.
+// All flow methods are no-ops on a nil flow (no journal configured).
+func (f *JournalFlow) Step(name string) (JournalStep, bool) {
+	if f == nil {
+		return JournalStep{}, false
+	}
+	for _, step := range f.Steps {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return JournalStep{}, false
+}
+
+// Intend records that a step is about to be executed. The step must not be executed
+// when the journal cannot be written (a crash would leave it unrecorded).
+func (f *JournalFlow) Intend(name string) error {
+	return f.setStep(JournalStep{Name: name, Status: JOURNAL_STEP_STATUS_INTENDED})
+}
+
+// Submit records the transaction submitted for a step. The transaction must not be
+// broadcast when the journal cannot be written (it would be sent again on resume).
+func (f *JournalFlow) Submit(name string, txHash string) error {
+	return f.setStep(JournalStep{Name: name, Status: JOURNAL_STEP_STATUS_SUBMITTED, TxHash: txHash})
+}
+
+// Confirm records that a step is completed, with its result (e.g. agent ID or CID).
+func (f *JournalFlow) Confirm(name string, result string) {
+	step, _ := f.Step(name)
+	step.Name = name
+	step.Status = JOURNAL_STEP_STATUS_CONFIRMED
+	step.Result = result
+	if err := f.setStep(step); err != nil {
+		log.Printf("warning: %v", err)
+	}
+}
+
+// Complete records that the flow is completed.
+func (f *JournalFlow) Complete() {
+	if f == nil {
+		return
+	}
+	f.Status = JOURNAL_FLOW_STATUS_COMPLETED
+	f.persist()
+}
+
+// Fail records that the flow cannot be completed.
+func (f *JournalFlow) Fail(reason string) {
+	if f == nil {
+		return
+	}
+	f.Status = JOURNAL_FLOW_STATUS_FAILED
+	f.Error = reason
+	f.persist()
+}
+
+// SetData records data of the flow (e.g. the agent ID once known).
+func (f *JournalFlow) SetData(key string, value any) {
+	if f == nil {
+		return
+	}
+	if f.Data == nil {
+		f.Data = map[string]any{}
+	}
+	f.Data[key] = value
+	f.persist()
+}
+
+// setStep adds or replaces a step and saves the flow.
+func (f *JournalFlow) setStep(step JournalStep) error {
+	if f == nil {
+		return nil
+	}
+	step.UpdatedAt = time.Now().Unix()
+	if idx := slices.IndexFunc(f.Steps, func(existing JournalStep) bool {
+		return existing.Name == step.Name
+	}); idx >= 0 {
+		f.Steps[idx] = step
+	} else {
+		f.Steps = append(f.Steps, step)
+	}
+	return f.save()
+}
+
+// save saves the flow (flows without a journal are kept in memory only).
+func (f *JournalFlow) save() error {
+	if f == nil || f.journal == nil {
+		return nil
+	}
+	f.UpdatedAt = time.Now().Unix()
+	if err := f.journal.write(f); err != nil {
+		return fmt.Errorf("failed to write transaction journal: %w", err)
+	}
+	return nil
+}
+
+// persist saves the flow and logs write failures. Records that do not precede a
+// transaction (e.g. completion) are a safety net, failing to write them must not abort
+// the flow.
+func (f *JournalFlow) persist() {
+	if err := f.save(); err != nil {
+		log.Printf("warning: %v", err)
+	}
+}
+
+// ...
+
+type JournalFlowKind string
+
+const (
+	JOURNAL_FLOW_REGISTER_IPFS        JournalFlowKind = "registerIPFS"
+	JOURNAL_FLOW_REGISTER_HTTP        JournalFlowKind = "registerHTTP"
+	JOURNAL_FLOW_REGISTER_MULTI_CHAIN JournalFlowKind = "registerMultiChain"
+)
+
+type JournalFlowStatus string
+
+const (
+	JOURNAL_FLOW_STATUS_IN_PROGRESS JournalFlowStatus = "inProgress"
+	JOURNAL_FLOW_STATUS_COMPLETED   JournalFlowStatus = "completed"
+	JOURNAL_FLOW_STATUS_FAILED      JournalFlowStatus = "failed"
+)
+
+type JournalStepStatus string
+
+const (
+	JOURNAL_STEP_STATUS_INTENDED  JournalStepStatus = "intended"
+	JOURNAL_STEP_STATUS_SUBMITTED JournalStepStatus = "submitted"
+	JOURNAL_STEP_STATUS_CONFIRMED JournalStepStatus = "confirmed"
+)
+
+type JournalFlow struct {
+	ID        string            `json:"id"`
+	Kind      JournalFlowKind   `json:"kind"`
+	Key       string            `json:"key"`
+	ChainID   types.ChainID     `json:"chainId"`
+	Status    JournalFlowStatus `json:"status"`
+	Steps     []JournalStep     `json:"steps"`
+	Data      map[string]any    `json:"data,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	CreatedAt types.Timestamp   `json:"createdAt"`
+	UpdatedAt types.Timestamp   `json:"updatedAt"`
+
+	journal *TransactionJournal
+}
+
+type JournalStep struct {
+	Name      string            `json:"name"`
+	Status    JournalStepStatus `json:"status"`
+	TxHash    string            `json:"txHash,omitempty"`
+	Result    string            `json:"result,omitempty"`
+	UpdatedAt types.Timestamp   `json:"updatedAt"`
+}
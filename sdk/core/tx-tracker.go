@@ -0,0 +1,288 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ryanchristo/agent0-go/sdk/utils"
+)
+
+// ReplacementOptions are the options for replacing pending transactions that are not
+// mined in time (e.g. because they are underpriced).
+type ReplacementOptions struct {
+	// Action is the replacement of a stuck transaction (speed up by default).
+	Action StuckTransactionAction
+
+	// ReplaceAfter is the time to wait for a transaction before replacing it in milliseconds.
+	ReplaceAfter int64
+
+	// MaxReplacements is the maximum number of replacements of a transaction.
+	MaxReplacements int64
+
+	// FeeBumpPercent is the fee increase of every replacement (nodes require at least 10%).
+	FeeBumpPercent int64
+
+	// Timeout is the total time to wait for the transaction in milliseconds.
+	Timeout int64
+}
+
+// TransactionResult is the result of tracking a transaction until it is mined.
+type TransactionResult struct {
+	// Receipt is the receipt of the mined transaction (original or replacement).
+	Receipt *ethtypes.Receipt
+
+	// Hash is the hash of the mined transaction.
+	Hash string
+
+	// ReplacedHashes are the hashes of the transactions that were replaced, in order.
+	ReplacedHashes []string
+
+	// Cancelled is true when the mined transaction is a cancellation.
+	Cancelled bool
+}
+
+// WaitForTransactionWithReplacement waits for a transaction to be mined, replacing it
+// with bumped fees (or cancelling it) when it is not mined after ReplaceAfter.
+// Any of the submitted transactions may be mined, so all of them are tracked. None of them
+// being mined before Timeout returns ErrTransactionTimeout.
+func (c *Web3Client) WaitForTransactionWithReplacement(txHash string, options ReplacementOptions) (TransactionResult, error) {
+	options = withReplacementDefaults(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.Timeout)*time.Millisecond)
+	defer cancel()
+
+	hashes := []common.Hash{common.HexToHash(txHash)}
+	cancellations := map[common.Hash]bool{}
+	replacements := int64(0)
+	lastSubmitted := time.Now()
+
+	pollTicker := time.NewTicker(time.Duration(utils.TIMEOUTS["TRANSACTION_POLL"]) * time.Millisecond)
+	defer pollTicker.Stop()
+
+	for {
+		// Check all submitted transactions (only one can be mined, they share the nonce)
+		for idx, hash := range hashes {
+			// Receipt errors are transient (not found, indexing in progress), like in bind.WaitMined
+			receipt, err := c.Provider.TransactionReceipt(ctx, hash)
+			if err != nil {
+				continue
+			}
+
+			result := TransactionResult{
+				Receipt:   receipt,
+				Hash:      hash.Hex(),
+				Cancelled: cancellations[hash],
+			}
+			for _, replaced := range slices.Delete(slices.Clone(hashes), idx, idx+1) {
+				result.ReplacedHashes = append(result.ReplacedHashes, replaced.Hex())
+			}
+			return result, nil
+		}
+
+		// Replace the latest transaction once it has been pending for too long
+		if replacements < options.MaxReplacements &&
+			time.Since(lastSubmitted) >= time.Duration(options.ReplaceAfter)*time.Millisecond {
+
+			latest := hashes[len(hashes)-1]
+			action := options.Action
+			if cancellations[latest] {
+				// A cancellation is only ever replaced by another cancellation
+				action = STUCK_TRANSACTION_ACTION_CANCEL
+			}
+
+			replacement, err := c.replaceTransaction(ctx, latest, action, options.FeeBumpPercent)
+			if err != nil {
+				log.Printf("warning: failed to replace transaction %s: %v", latest.Hex(), err)
+			} else {
+				hashes = append(hashes, replacement)
+				cancellations[replacement] = action == STUCK_TRANSACTION_ACTION_CANCEL
+			}
+			replacements++
+			lastSubmitted = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return TransactionResult{}, fmt.Errorf("%w: %s after %d ms (submitted %d transactions)",
+				ErrTransactionTimeout, txHash, options.Timeout, len(hashes))
+		case <-pollTicker.C:
+		}
+	}
+}
+
+// SpeedUpTransaction replaces a pending transaction with the same transaction with bumped
+// fees and returns the hash of the replacement.
+func (c *Web3Client) SpeedUpTransaction(txHash string) string {
+	replacement, err := c.TrySpeedUpTransaction(txHash)
+	if err != nil {
+		log.Fatalf("Failed to speed up transaction: %v", err)
+	}
+	return replacement
+}
+
+// TrySpeedUpTransaction is SpeedUpTransaction returning an error when the transaction
+// cannot be replaced (e.g. it is already mined).
+func (c *Web3Client) TrySpeedUpTransaction(txHash string) (string, error) {
+	replacement, err := c.replaceTransaction(
+		context.Background(),
+		common.HexToHash(txHash),
+		STUCK_TRANSACTION_ACTION_SPEED_UP,
+		utils.DEFAULTS["TRANSACTION_FEE_BUMP_PERCENT"],
+	)
+	if err != nil {
+		return "", err
+	}
+	return replacement.Hex(), nil
+}
+
+// CancelTransaction replaces a pending transaction with an empty self-transfer at the same
+// nonce and returns the hash of the cancellation.
+func (c *Web3Client) CancelTransaction(txHash string) string {
+	replacement, err := c.TryCancelTransaction(txHash)
+	if err != nil {
+		log.Fatalf("Failed to cancel transaction: %v", err)
+	}
+	return replacement
+}
+
+// TryCancelTransaction is CancelTransaction returning an error when the transaction
+// cannot be replaced (e.g. it is already mined).
+func (c *Web3Client) TryCancelTransaction(txHash string) (string, error) {
+	replacement, err := c.replaceTransaction(
+		context.Background(),
+		common.HexToHash(txHash),
+		STUCK_TRANSACTION_ACTION_CANCEL,
+		utils.DEFAULTS["TRANSACTION_FEE_BUMP_PERCENT"],
+	)
+	if err != nil {
+		return "", err
+	}
+	return replacement.Hex(), nil
+}
+
+// replaceTransaction sends a replacement of a pending transaction at the same nonce.
+func (c *Web3Client) replaceTransaction(
+	ctx context.Context,
+	txHash common.Hash,
+	action StuckTransactionAction,
+	feeBumpPercent int64,
+) (common.Hash, error) {
+	if c.Signer == nil {
+		return common.Hash{}, errors.New("no signer available for transaction")
+	}
+
+	tx, isPending, err := c.Provider.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if !isPending {
+		return common.Hash{}, errors.New("transaction is already mined")
+	}
+
+	// Cancellations are empty self-transfers
+	to, value, data, gas := tx.To(), tx.Value(), tx.Data(), tx.Gas()
+	if action == STUCK_TRANSACTION_ACTION_CANCEL {
+		self := c.Signer.Address()
+		to, value, data, gas = &self, new(big.Int), nil, 21000
+	}
+
+	// Bump the fees of the pending transaction, and follow the market if it moved further
+	fees := c.transactionFees(ctx, TransactionOptions{})
+	var replacement *ethtypes.Transaction
+	if tx.Type() == ethtypes.LegacyTxType {
+		gasPrice := bigMax(bumpFee(tx.GasPrice(), feeBumpPercent), fees.MaxFeePerGas())
+		replacement = ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	} else {
+		gasTipCap := bumpFee(tx.GasTipCap(), feeBumpPercent)
+		gasFeeCap := bumpFee(tx.GasFeeCap(), feeBumpPercent)
+		if fees.IsDynamic() {
+			gasTipCap = bigMax(gasTipCap, fees.GasTipCap)
+			gasFeeCap = bigMax(gasFeeCap, fees.GasFeeCap)
+		}
+		replacement = ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:    c.ChainID.BigInt(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  gasTipCap,
+			GasFeeCap:  bigMax(gasFeeCap, gasTipCap),
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: tx.AccessList(),
+		})
+	}
+
+	if err := c.checkFeeBudget(replacement); err != nil {
+		return common.Hash{}, err
+	}
+
+	signedTx, err := c.Signer.SignTx(replacement, c.ChainID.BigInt())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign replacement: %w", err)
+	}
+
+	if err := c.Provider.SendTransaction(ctx, signedTx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send replacement: %w", err)
+	}
+
+	return signedTx.Hash(), nil
+}
+
+// withReplacementDefaults sets the default values of the unset replacement options.
+func withReplacementDefaults(options ReplacementOptions) ReplacementOptions {
+	if options.Action == "" {
+		options.Action = STUCK_TRANSACTION_ACTION_SPEED_UP
+	}
+	if options.ReplaceAfter == 0 {
+		options.ReplaceAfter = utils.TIMEOUTS["TRANSACTION_REPLACE_AFTER"]
+	}
+	if options.MaxReplacements == 0 {
+		options.MaxReplacements = utils.DEFAULTS["TRANSACTION_MAX_REPLACEMENTS"]
+	}
+	if options.FeeBumpPercent == 0 {
+		options.FeeBumpPercent = utils.DEFAULTS["TRANSACTION_FEE_BUMP_PERCENT"]
+	}
+	if options.Timeout == 0 {
+		options.Timeout = options.ReplaceAfter * (options.MaxReplacements + 2)
+	}
+	return options
+}
+
+// bumpFee increases a fee by a percentage (rounded up).
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// bigMax returns the larger of two integers.
+func bigMax(a, b *big.Int) *big.Int {
+	if b != nil && b.Cmp(a) > 0 {
+		return new(big.Int).Set(b)
+	}
+	return new(big.Int).Set(a)
+}
+
+// ...
+
+type StuckTransactionAction string
+
+const (
+	STUCK_TRANSACTION_ACTION_SPEED_UP StuckTransactionAction = "speedUp"
+	STUCK_TRANSACTION_ACTION_CANCEL   StuckTransactionAction = "cancel"
+)
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewSafeTransactionBatch(t *testing.T) {
+	const safe = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	const registry = "0x8004A818BFB912233c491871b3d84c89A494BD9e"
+
+	register := UnsignedTransaction{ChainID: 84532, To: registry, Value: "0", Data: "0x01"}
+	setAgentURI := UnsignedTransaction{ChainID: 84532, To: registry, Value: "0", Data: "0x02"}
+	batch, err := NewSafeTransactionBatch(safe, "register", register, setAgentURI)
+	if err != nil {
+		t.Fatalf("NewSafeTransactionBatch error: %v", err)
+	}
+	if batch.ChainID != "84532" || len(batch.Transactions) != 2 || batch.Transactions[1].Data != "0x02" {
+		t.Errorf("NewSafeTransactionBatch() = %+v, want both transactions on chain 84532", batch)
+	}
+
+	otherChain := UnsignedTransaction{ChainID: 11155111, To: registry, Value: "0", Data: "0x02"}
+	if _, err := NewSafeTransactionBatch(safe, "register", register, otherChain); !errors.Is(err, ErrMixedChainBatch) {
+		t.Errorf("NewSafeTransactionBatch error = %v, want ErrMixedChainBatch", err)
+	}
+	if _, err := NewSafeTransactionBatch(safe, "empty"); err == nil {
+		t.Errorf("NewSafeTransactionBatch error = nil for an empty batch")
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTransactionJournalRecordsFlows(t *testing.T) {
+	journal, err := NewTransactionJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flow := journal.Begin(JOURNAL_FLOW_REGISTER_HTTP, "agent", 84532, map[string]any{"agentUri": "https://example.com/agent.json"})
+	if err := flow.Intend("register"); err != nil {
+		t.Fatalf("Intend error: %v", err)
+	}
+	if err := flow.Submit("register", "0x01"); err != nil {
+		t.Fatalf("Submit error: %v", err)
+	}
+
+	// A flow interrupted after the submission is found again with its transaction
+	found, ok := journal.Find(JOURNAL_FLOW_REGISTER_HTTP, "agent")
+	if !ok || found.ID != flow.ID {
+		t.Fatalf("Find() = %v, %v, want flow %s", found, ok, flow.ID)
+	}
+	step, ok := found.Step("register")
+	if !ok || step.Status != JOURNAL_STEP_STATUS_SUBMITTED || step.TxHash != "0x01" {
+		t.Errorf("register step = %+v, want submitted with transaction 0x01", step)
+	}
+	if found.Data["agentUri"] != "https://example.com/agent.json" {
+		t.Errorf("Data = %v, want the agent URI", found.Data)
+	}
+
+	found.Confirm("register", "84532:7")
+	found.Complete()
+	if _, ok := journal.Find(JOURNAL_FLOW_REGISTER_HTTP, "agent"); ok {
+		t.Errorf("Find() found the completed flow")
+	}
+	completed, ok := journal.Get(flow.ID)
+	if !ok || completed.Status != JOURNAL_FLOW_STATUS_COMPLETED {
+		t.Fatalf("Get() = %+v, %v, want the completed flow", completed, ok)
+	}
+	if step, _ := completed.Step("register"); step.Status != JOURNAL_STEP_STATUS_CONFIRMED || step.Result != "84532:7" || step.TxHash != "0x01" {
+		t.Errorf("register step = %+v, want confirmed with result 84532:7", step)
+	}
+}
+
+func TestTransactionJournalReportsWriteFailures(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewTransactionJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flow := journal.Begin(JOURNAL_FLOW_REGISTER_IPFS, "agent", 84532, nil)
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Intend("register"); err == nil {
+		t.Errorf("Intend error = nil, want the write failure")
+	}
+	if err := flow.Submit("register", "0x01"); err == nil {
+		t.Errorf("Submit error = nil, want the write failure")
+	}
+
+	// Flows without journal are no-ops
+	var noJournal *JournalFlow
+	if err := noJournal.Intend("register"); err != nil {
+		t.Errorf("Intend error on a nil flow: %v", err)
+	}
+	if err := noJournal.Submit("register", "0x01"); err != nil {
+		t.Errorf("Submit error on a nil flow: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWaitForTransactionResultTimeout(t *testing.T) {
+	const txHash = "0x0101010101010101010101010101010101010101010101010101010101010101"
+
+	// The transaction is never mined
+	provider := newFakeRPC(t, func(method string, params []json.RawMessage) (any, error) {
+		if method != "eth_getTransactionReceipt" {
+			return nil, errors.New("unexpected method " + method)
+		}
+		return nil, nil
+	})
+
+	tests := []struct {
+		name        string
+		replacement *ReplacementOptions
+	}{
+		{name: "without replacement"},
+		{name: "with replacement", replacement: &ReplacementOptions{ReplaceAfter: 60000, Timeout: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Web3Client{Provider: provider, Replacement: tt.replacement}
+
+			if _, err := client.WaitForTransactionResult(txHash, 50); !errors.Is(err, ErrTransactionTimeout) {
+				t.Errorf("WaitForTransactionResult error = %v, want ErrTransactionTimeout", err)
+			}
+		})
+	}
+}
@@ -3,25 +3,34 @@ package core
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	bindv2 "github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 
 	"github.com/ryanchristo/agent0-go/sdk/types"
+	"github.com/ryanchristo/agent0-go/sdk/utils"
 )
 
-// TransactionOptions are the options for a transaction.
+// TransactionOptions are the options for a transaction (zero values are unset).
+// Setting GasPrice or MaxFeePerGas overrides the fee strategy of the client.
 type TransactionOptions struct {
 	GasLimit             big.Int
 	GasPrice             big.Int
@@ -32,25 +41,64 @@ type TransactionOptions struct {
 // Web3Client is a client for interacting with the Ethereum blockchain.
 type Web3Client struct {
 	Provider *ethclient.Client
-	Signer   bind.SignerFn
+	Signer   Signer
 	ChainID  types.ChainID
 
-	// TODO: signer limitations
-	privateKey *ecdsa.PrivateKey
+	// FeeStrategy computes transaction fees (the chain default is used when unset).
+	FeeStrategy FeeStrategy
+
+	// MaxFeeBudget is the maximum fee of a single transaction in wei (gas limit times
+	// max fee per gas); transactions above the budget are not signed.
+	MaxFeeBudget *big.Int
+
+	// Replacement replaces transactions that are not mined in time when waiting for them
+	// (transactions are never replaced when unset).
+	Replacement *ReplacementOptions
+
+	nonces *NonceManager
+	abis   sync.Map // contract address -> parsed ABI (see GetContract)
+
+	transport *FailoverTransport // nil for non HTTP(S) endpoints
+
+	multicallMu       sync.Mutex
+	multicallDeployed *bool // nil until checked (see hasMulticall3)
 }
 
+// NONCE_CONFLICT_RETRIES is the number of attempts to send a transaction rejected
+// because of a nonce conflict.
+const NONCE_CONFLICT_RETRIES = 3
+
+// ErrFeeBudgetExceeded is returned when the maximum fee of a transaction exceeds the budget.
+var ErrFeeBudgetExceeded = errors.New("transaction fee budget exceeded")
+
+// ErrTransactionCancelled is returned when a cancellation is mined instead of a transaction.
+var ErrTransactionCancelled = errors.New("transaction was cancelled")
+
+// ErrTransactionTimeout is returned when a transaction is not mined in time.
+var ErrTransactionTimeout = errors.New("transaction was not mined in time")
+
 // NewWeb3Client creates a new Web3Client instance.
+// The signer is either a hex encoded private key or a Signer implementation.
 func NewWeb3Client(rpcURL string, signerOrKey any) *Web3Client {
+	return NewFailoverWeb3Client([]string{rpcURL}, signerOrKey, RetryPolicy{})
+}
+
+// NewFailoverWeb3Client creates a new Web3Client instance connected to several RPC
+// endpoints of the same chain, tried in order (see FailoverTransport). Transient errors
+// of reads are retried according to the retry policy (defaults when unset).
+func NewFailoverWeb3Client(rpcURLs []string, signerOrKey any, policy RetryPolicy) *Web3Client {
 	web3Client := &Web3Client{}
 
 	// Create client
-	client, err := ethclient.Dial(rpcURL)
+	client, transport, err := dialRPC(rpcURLs, policy)
 	if err != nil {
 		log.Fatalf("Failed to connect to Ethereum node: %v", err)
 	}
 
 	// Set provider
 	web3Client.Provider = client
+	web3Client.nonces = NewNonceManager(client)
+	web3Client.transport = transport
 
 	// Get chain ID
 	chainID, err := client.ChainID(context.Background())
@@ -61,48 +109,70 @@ func NewWeb3Client(rpcURL string, signerOrKey any) *Web3Client {
 	// Set chain ID
 	web3Client.ChainID = types.ChainID(chainID.Int64())
 
-	if signerOrKey != nil {
-
-		// Signer is a string (private key)
-		if key, ok := signerOrKey.(string); ok {
-
-			// Trim the private key (remove whitespace)
-			trimmedKey := strings.TrimSpace(key)
-			if trimmedKey == "" {
-				log.Fatal("Private key cannot be empty")
-			}
-
-			// Parse the private key
-			privateKey, err := crypto.HexToECDSA(trimmedKey)
-			if err != nil {
-				log.Fatalf("Failed to parse private key: %v", err)
+	// Endpoints of another chain must never be failed over to
+	if len(rpcURLs) > 1 {
+		for _, health := range transport.CheckHealth(context.Background()) {
+			switch {
+			case !health.Healthy:
+				log.Printf("warning: RPC endpoint %s is unavailable: %v", health.URL, health.Err)
+			case health.ChainID != web3Client.ChainID:
+				log.Printf("warning: RPC endpoint %s is on chain %d instead of %d, disabling it",
+					health.URL, health.ChainID, web3Client.ChainID)
+				transport.Disable(health.URL)
 			}
+		}
+	}
 
-			// Create signer from private key and chain ID
-			txOpts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
-			if err != nil {
-				log.Fatalf("Failed to create signer: %v", err)
-			}
+	switch signer := signerOrKey.(type) {
+	case nil:
+		// read-only mode
+	case string:
+		// Signer is a string (private key)
+		privateKeySigner, err := NewPrivateKeySigner(signer)
+		if err != nil {
+			log.Fatalf("Failed to create signer: %v", err)
+		}
+		web3Client.Signer = privateKeySigner
+	case Signer:
+		web3Client.Signer = signer
+	default:
+		log.Fatalf("Invalid signer or key: expected a private key or a Signer, got %T", signerOrKey)
+	}
 
-			web3Client.Signer = txOpts.Signer
+	return web3Client
+}
 
-			// TODO: signer limitations
-			web3Client.privateKey = privateKey
+// dialRPC connects to the RPC endpoints. HTTP(S) endpoints use the failover transport,
+// other endpoints (e.g. WebSocket) are dialed directly and support a single endpoint.
+func dialRPC(rpcURLs []string, policy RetryPolicy) (*ethclient.Client, *FailoverTransport, error) {
+	if len(rpcURLs) == 1 && !strings.HasPrefix(rpcURLs[0], "http://") && !strings.HasPrefix(rpcURLs[0], "https://") {
+		client, err := ethclient.Dial(rpcURLs[0])
+		return client, nil, err
+	}
 
-		} else if signer, ok := signerOrKey.(bind.SignerFn); ok {
+	transport, err := NewFailoverTransport(rpcURLs, policy)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			// TODO: signer limitations
-			log.Print("warning: signer limitations")
+	rpcClient, err := rpc.DialOptions(
+		context.Background(),
+		rpcURLs[0], // requests are redirected to the available endpoint by the transport
+		rpc.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			// Signer is already signer
-			web3Client.Signer = signer
+	return ethclient.NewClient(rpcClient), transport, nil
+}
 
-		} else {
-			log.Fatal("Invalid signer or key")
-		}
+// RPCHealth checks the health of the RPC endpoints (nil for non HTTP(S) endpoints).
+func (c *Web3Client) RPCHealth() []EndpointHealth {
+	if c.transport == nil {
+		return nil
 	}
-
-	return web3Client
+	return c.transport.CheckHealth(context.Background())
 }
 
 // Initialize initializes the Web3Client.
@@ -119,58 +189,24 @@ func (c *Web3Client) GetContract(address types.Address, abi string) *Contract {
 		log.Fatalf("Failed to parse ABI: %v", err)
 	}
 
-	// Create bound contract
+	// Create bound contract (the ABI is kept to pack calls by method name)
 	contract := bind.NewBoundContract(
-		common.HexToAddress(address),
+		address.Common(),
 		contractABI,
 		c.Provider, // caller
 		c.Provider, // transactor
 		c.Provider, // filterer
 	)
+	c.abis.Store(contract.Address(), contractABI)
 
 	return contract
 }
 
-// CallContract calls a contract method (view/pure function) and returns the result.
+// CallContract calls a contract method (view/pure function) by name and returns the result.
+// The SDK itself uses the typed registry bindings (see callContract).
 func (c *Web3Client) CallContract(contract *Contract, methodName string, args ...any) any {
 	ctx := context.Background()
 
-	// Check if the contract is the identity registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["IDENTITY"]) {
-		identityABI, err := ethabi.JSON(strings.NewReader(IDENTITY_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse identity registry ABI: %v", err)
-		}
-		_, exists := identityABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-	}
-
-	// Check if the contract is the reputation registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["REPUTATION"]) {
-		reputationABI, err := ethabi.JSON(strings.NewReader(REPUTATION_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse reputation registry ABI: %v", err)
-		}
-		_, exists := reputationABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-	}
-
-	// Check if the contract is the validation registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["VALIDATION"]) {
-		validationABI, err := ethabi.JSON(strings.NewReader(VALIDATION_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse validation registry ABI: %v", err)
-		}
-		_, exists := validationABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-	}
-
 	var result []any
 
 	// Create call options
@@ -178,237 +214,272 @@ func (c *Web3Client) CallContract(contract *Contract, methodName string, args ..
 		Context: ctx,
 	}
 
-	return contract.Call(opts, &result, methodName, args...)
+	if err := contract.Call(opts, &result, methodName, args...); err != nil {
+		log.Fatalf("Failed to call contract method %s: %v", methodName, decodeTransactionError(err))
+	}
+
+	// Unwrap single return values
+	if len(result) == 1 {
+		return result[0]
+	}
+
+	return result
 }
 
 // TransactContract executes a contract transaction and returns the transaction hash.
-func (c *Web3Client) TransactContract(contract *Contract, methodName string, options TransactionOptions, args ...any) string {
+// Reverts are returned as a *RevertError, which unwraps to the matching typed error
+// (e.g. errors.Is(err, ErrNotAgentOwner)).
+func (c *Web3Client) TransactContract(contract *Contract, methodName string, options TransactionOptions, args ...any) (string, error) {
 	if c.Signer == nil {
-		log.Fatal("Cannot execute transaction: SDK is in read-only mode.")
+		return "", errors.New("cannot execute transaction: SDK is in read-only mode")
 	}
 
-	// Special handling for register() function with multiple overloads
-	if methodName == "register" {
-		return c.registerAgent(contract, options, args...)
+	data, err := c.packContractCall(contract, methodName, args...)
+	if err != nil {
+		return "", err
 	}
 
-	var data []byte
-
-	// Check if the contract is the identity registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["IDENTITY"]) {
-		identityABI, err := ethabi.JSON(strings.NewReader(IDENTITY_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse identity registry ABI: %v", err)
-		}
-		_, exists := identityABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-		data, err = identityABI.Pack(methodName, args...)
-		if err != nil {
-			log.Fatalf("Failed to pack data: %v", err)
-		}
-	}
+	return c.transact(contract, options, data)
+}
 
-	// Check if the contract is the reputation registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["REPUTATION"]) {
-		reputationABI, err := ethabi.JSON(strings.NewReader(REPUTATION_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse reputation registry ABI: %v", err)
-		}
-		_, exists := reputationABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-		data, err = reputationABI.Pack(methodName, args...)
-		if err != nil {
-			log.Fatalf("Failed to pack data: %v", err)
-		}
-	}
+// transact sends a contract transaction with packed call data and returns the
+// transaction hash.
+func (c *Web3Client) transact(contract *Contract, options TransactionOptions, data []byte) (string, error) {
+	return c.transactSigned(contract, options, data, nil)
+}
 
-	// Check if the contract is the validation registry and the provided method exists
-	if contract.Address() == common.HexToAddress(DEFAULT_REGISTRIES[c.ChainID]["VALIDATION"]) {
-		validationABI, err := ethabi.JSON(strings.NewReader(VALIDATION_REGISTRY_ABI))
-		if err != nil {
-			log.Fatalf("Failed to parse validation registry ABI: %v", err)
-		}
-		_, exists := validationABI.Methods[methodName]
-		if !exists {
-			log.Fatal("Method not found")
-		}
-		data, err = validationABI.Pack(methodName, args...)
-		if err != nil {
-			log.Fatalf("Failed to pack data: %v", err)
-		}
+// transactSigned sends a contract transaction like transact, calling signed with the hash
+// of the signed transaction before it is broadcast (e.g. to journal it). The transaction
+// is not broadcast when signed returns an error.
+func (c *Web3Client) transactSigned(
+	contract *Contract,
+	options TransactionOptions,
+	data []byte,
+	signed func(txHash string) error,
+) (string, error) {
+	if c.Signer == nil {
+		return "", errors.New("cannot execute transaction: SDK is in read-only mode")
 	}
 
-	ctx := context.Background()
-
-	nonce, err := c.Provider.PendingNonceAt(ctx, common.HexToAddress(c.GetAddress()))
+	// Send transaction directly with encoded data (no function call resolution needed)
+	tx, err := c.sendTransaction(contract, options, data, signed)
 	if err != nil {
-		log.Fatalf("Failed to get pending nonce: %v", err)
+		return "", err
 	}
 
-	if options.GasPrice.Sign() == 0 {
-		suggestedGasPrice, err := c.Provider.SuggestGasPrice(ctx)
-		if err != nil {
-			log.Fatalf("Failed to suggest gas price: %v", err)
-		}
-		options.GasPrice = *suggestedGasPrice
-	}
+	return tx.Hash().Hex(), nil
+}
 
-	if options.GasLimit.Sign() == 0 {
-		contractAddr := contract.Address()
-		estimatedGas, err := c.Provider.EstimateGas(ctx, ethereum.CallMsg{
-			To:   &contractAddr,
-			Data: data,
-		})
-		if err != nil {
-			log.Fatalf("Failed to estimate gas needed: %v", err)
-		}
-		options.GasLimit = *big.NewInt(int64(estimatedGas))
-	}
+// callContract calls a contract with packed call data and unpacks the result (used with
+// the typed registry bindings, e.g. identityRegistryBinding.PackOwnerOf).
+func callContract[T any](contract *Contract, data []byte, unpack func([]byte) (T, error)) (T, error) {
+	return callContractAt(contract, nil, data, unpack)
+}
 
-	// Build transaction options
-	opts := &bind.TransactOpts{
-		From:       common.HexToAddress(c.GetAddress()),
-		Nonce:      big.NewInt(int64(nonce)),
-		Signer:     c.Signer,
-		Value:      nil,
-		GasPrice:   &options.GasPrice,
-		GasFeeCap:  &options.MaxFeePerGas,
-		GasTipCap:  &options.MaxPriorityFeePerGas,
-		GasLimit:   options.GasLimit.Uint64(),
-		AccessList: nil,
-		Context:    ctx,
-		NoSend:     false,
-	}
-
-	// Send transaction
-	tx, err := contract.Transact(opts, methodName, args...)
+// callContractAt calls a read-only contract method at a block (latest block when nil).
+func callContractAt[T any](contract *Contract, blockNumber *big.Int, data []byte, unpack func([]byte) (T, error)) (T, error) {
+	opts := &bind.CallOpts{Context: context.Background(), BlockNumber: blockNumber}
+	result, err := bindv2.Call(contract, opts, data, unpack)
 	if err != nil {
-		log.Fatalf("Failed to send transaction: %v", err)
+		return result, decodeTransactionError(err)
 	}
-
-	return tx.Hash().Hex()
+	return result, nil
 }
 
-// registerAgent is a router wrapper for register() function overloads.
-// The function selected the correct overload based on the following arguments:
-// - register() - no arguments
-// - register(string tokenUri) - one argument
-// - register(string tokenUri, tuple[] metadata) - two arguments
-func (c *Web3Client) registerAgent(contract *Contract, options TransactionOptions, args ...any) string {
-	if c.Signer == nil {
-		log.Fatal("No signer available for transaction")
-	}
-
-	// Determine which overload to use based on arguments
-	var methodName string
-	var callArgs []any
-
-	if len(args) == 0 {
-		methodName = "register()"
-		callArgs = []any{}
-	} else if len(args) == 1 {
-		methodName = "register(string)"
-		callArgs = []any{args[0]}
-	} else if len(args) == 2 {
-		methodName = "register(string,(string,bytes)[])"
-		callArgs = []any{args[0], args[1]}
-	} else {
-		log.Fatalf("Invalid number of arguments for register() function: %d", len(args))
-	}
-
-	// Parse the JSON into an ABI object
-	parsedABI, err := ethabi.JSON(strings.NewReader(IDENTITY_REGISTRY_ABI))
+// SimulateContract simulates a contract transaction without sending it (dry run): the
+// transaction is executed with eth_call, and its gas and fee are estimated.
+func (c *Web3Client) SimulateContract(contract *Contract, methodName string, options TransactionOptions, args ...any) SimulationResult {
+	data, err := c.packContractCall(contract, methodName, args...)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("Failed to simulate contract method %s: %v", methodName, err)
 	}
 
-	// Access the function fragment
-	_, exists := parsedABI.Methods[methodName]
-	if !exists {
-		log.Fatal("Method not found")
+	return c.simulate(contract, options, methodName, data)
+}
+
+// simulate simulates a contract transaction with packed call data.
+func (c *Web3Client) simulate(contract *Contract, options TransactionOptions, methodName string, data []byte) SimulationResult {
+	// Read-only clients simulate from the zero address
+	from := common.Address{}
+	if c.Signer != nil {
+		from = c.Signer.Address()
 	}
+	to := contract.Address()
 
-	// Encode function data to avoid ambiguity - this bypasses function resolution
-	data, err := parsedABI.Pack(methodName, callArgs...)
-	if err != nil {
-		log.Fatalf("Failed to pack data: %v", err)
+	result := SimulationResult{
+		From:     types.AddressFromCommon(from),
+		To:       types.AddressFromCommon(to),
+		Method:   methodName,
+		Calldata: hexutil.Encode(data),
 	}
 
 	ctx := context.Background()
+	msg := ethereum.CallMsg{From: from, To: &to, Data: data}
 
-	nonce, err := c.Provider.PendingNonceAt(ctx, common.HexToAddress(c.GetAddress()))
+	returnData, err := c.Provider.CallContract(ctx, msg, nil)
 	if err != nil {
-		log.Fatalf("Failed to get pending nonce: %v", err)
+		return result.failed(err)
 	}
+	result.ReturnData = hexutil.Encode(returnData)
 
-	if options.GasPrice.Sign() == 0 {
-		suggestedGasPrice, err := c.Provider.SuggestGasPrice(ctx)
-		if err != nil {
-			log.Fatalf("Failed to suggest gas price: %v", err)
+	// Estimate the gas limit (unless set) and the fee of the transaction
+	result.GasLimit = options.GasLimit.Uint64()
+	if result.GasLimit == 0 {
+		if result.GasLimit, err = c.Provider.EstimateGas(ctx, msg); err != nil {
+			return result.failed(err)
 		}
-		options.GasPrice = *suggestedGasPrice
 	}
+	result.Fees = c.transactionFees(ctx, options)
+	result.MaxFee = new(big.Int).Mul(new(big.Int).SetUint64(result.GasLimit), result.Fees.MaxFeePerGas())
+	result.WithinBudget = c.MaxFeeBudget == nil || result.MaxFee.Cmp(c.MaxFeeBudget) <= 0
+	result.Success = true
 
-	if options.GasLimit.Sign() == 0 {
-		contractAddr := contract.Address()
-		estimatedGas, err := c.Provider.EstimateGas(ctx, ethereum.CallMsg{
-			To:   &contractAddr,
-			Data: data,
-		})
+	return result
+}
+
+// packContractCall encodes the call data of a contract method with the ABI of the
+// contract. Overloaded methods (e.g. register) are selected by their signature or by the
+// number of arguments:
+// - register() - no arguments
+// - register(string tokenUri) - one argument
+// - register(string tokenUri, tuple[] metadata) - two arguments
+func (c *Web3Client) packContractCall(contract *Contract, methodName string, args ...any) ([]byte, error) {
+	value, ok := c.abis.Load(contract.Address())
+	if !ok {
+		return nil, fmt.Errorf("unknown ABI of contract %s (contracts are created with GetContract)", contract.Address().Hex())
+	}
+	contractABI := value.(ethabi.ABI)
+
+	// Overloads are named register, register0, ... in the parsed ABI
+	for name, method := range contractABI.Methods {
+		if method.Sig != methodName && (method.RawName != methodName || len(method.Inputs) != len(args)) {
+			continue
+		}
+
+		// Encode function data to avoid ambiguity - this bypasses function resolution
+		data, err := contractABI.Pack(name, args...)
 		if err != nil {
-			log.Fatalf("Failed to estimate gas needed: %v", err)
+			return nil, fmt.Errorf("failed to pack data: %w", err)
 		}
-		options.GasLimit = *big.NewInt(int64(estimatedGas))
+		return data, nil
 	}
 
-	// Build transaction options
-	opts := &bind.TransactOpts{
-		From:       common.HexToAddress(c.GetAddress()),
-		Nonce:      big.NewInt(int64(nonce)),
-		Signer:     c.Signer,
-		Value:      nil,
-		GasPrice:   &options.GasPrice,
-		GasFeeCap:  &options.MaxFeePerGas,
-		GasTipCap:  &options.MaxPriorityFeePerGas,
-		GasLimit:   options.GasLimit.Uint64(),
-		AccessList: nil,
-		Context:    ctx,
-		NoSend:     false,
-	}
+	return nil, fmt.Errorf("method %s not found", methodName)
+}
 
-	// Send transaction directly with encoded data (no function call resolution needed)
-	tx, err := contract.RawTransact(opts, data)
+// WaitForTransaction waits for a transaction to be mined and returns the receipt.
+// With replacement options set on the client, stuck transactions are replaced and the
+// timeout is extended to cover the replacements. Fails when a cancellation is mined
+// instead of the transaction or when it is not mined in time (see WaitForTransactionResult).
+func (c *Web3Client) WaitForTransaction(txHash string, timeout int64) *ethtypes.Receipt {
+	result, err := c.WaitForTransactionResult(txHash, timeout)
 	if err != nil {
-		log.Fatalf("Failed to send transaction: %v", err)
+		log.Fatalf("Failed to wait for transaction to be mined: %v", err)
+	}
+	if result.Cancelled {
+		log.Fatalf("Transaction %s was cancelled by %s (replaced transactions: %s)",
+			txHash, result.Hash, strings.Join(result.ReplacedHashes, ", "))
+	}
+	if common.HexToHash(result.Hash) != common.HexToHash(txHash) {
+		log.Printf("warning: transaction %s was mined as replacement %s", txHash, result.Hash)
 	}
 
-	return tx.Hash().Hex()
+	return result.Receipt
 }
 
-// WaitForTransaction waits for a transaction to be mined and returns the receipt.
-func (c *Web3Client) WaitForTransaction(txHash string, timeout int64) *ethtypes.Receipt {
+// WaitForTransactionResult waits for a transaction to be mined and returns the mined
+// transaction (the original, a replacement or a cancellation) with the replaced hashes.
+// Transactions not mined in time return ErrTransactionTimeout.
+func (c *Web3Client) WaitForTransactionResult(txHash string, timeout int64) (TransactionResult, error) {
 	if timeout == 0 {
 		timeout = 60000 // why not utils.TIMEOUTS["TRANSACTION_WAIT"] ?
 	}
 
+	if c.Replacement != nil {
+		options := withReplacementDefaults(*c.Replacement)
+		options.Timeout = max(options.Timeout, timeout)
+		return c.WaitForTransactionWithReplacement(txHash, options)
+	}
+
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
 	defer cancel()
 
 	receipt, err := bind.WaitMinedHash(ctx, c.Provider, common.HexToHash(txHash))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return TransactionResult{}, fmt.Errorf("%w: %s after %d ms", ErrTransactionTimeout, txHash, timeout)
+	}
 	if err != nil {
-		log.Fatalf("Failed to wait for transaction to be mined: %v", err)
+		return TransactionResult{}, fmt.Errorf("failed to wait for transaction %s: %w", txHash, err)
 	}
 
-	return receipt
+	return TransactionResult{Receipt: receipt, Hash: receipt.TxHash.Hex(), ReplacedHashes: []string{}}, nil
 }
 
-// GetEvents gets the events from the contract and returns a list of logs.
-func (c *Web3Client) GetEvents(contract *Contract, eventName string, fromBlock, toBlock int64) []ethtypes.Log {
+// waitForSuccess waits for a transaction to be mined and returns its receipt, or an error
+// when it was cancelled (ErrTransactionCancelled), not mined in time (ErrTransactionTimeout)
+// or reverted (RevertError).
+func (c *Web3Client) waitForSuccess(txHash string) (*ethtypes.Receipt, error) {
+	result, err := c.WaitForTransactionResult(txHash, 0)
+	if err != nil {
+		return nil, err
+	}
+	if result.Cancelled {
+		return nil, fmt.Errorf("%w: %s by %s", ErrTransactionCancelled, txHash, result.Hash)
+	}
+	if err := c.ReceiptError(result.Receipt); err != nil {
+		return nil, err
+	}
+	return result.Receipt, nil
+}
+
+// transactionExists checks if a transaction is known to the node (pending or mined).
+// Only a transaction reported as not found is considered dropped.
+func (c *Web3Client) transactionExists(txHash string) bool {
+	_, _, err := c.Provider.TransactionByHash(context.Background(), common.HexToHash(txHash))
+	return !errors.Is(err, ethereum.NotFound)
+}
+
+// GetEvents gets the events from the contract and returns a list of logs. The optional
+// query filters the indexed event arguments (one list of accepted values per argument).
+func (c *Web3Client) GetEvents(contract *Contract, eventName string, fromBlock, toBlock int64, query ...[]any) []ethtypes.Log {
+	events, err := c.filterEvents(contract, eventName, fromBlock, toBlock, query...)
+	if err != nil {
+		log.Fatalf("Failed to get events: %v", err)
+	}
+	return events
+}
+
+// getEventsPaged gets the events from the contract from a block to the latest block, in
+// block ranges of LOG_BLOCK_RANGE (halved when a range is rejected, e.g. by the block
+// range or result limits of public RPCs).
+func (c *Web3Client) getEventsPaged(contract *Contract, eventName string, fromBlock int64, query ...[]any) ([]ethtypes.Log, error) {
+	latestBlock, err := c.Provider.BlockNumber(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	events := []ethtypes.Log{}
+	blockRange := utils.DEFAULTS["LOG_BLOCK_RANGE"]
+	for start := fromBlock; start <= int64(latestBlock); {
+		end := min(start+blockRange-1, int64(latestBlock))
+		page, err := c.filterEvents(contract, eventName, start, end, query...)
+		if err != nil {
+			if blockRange == 1 {
+				return nil, fmt.Errorf("failed to get events of blocks %d-%d: %w", start, end, err)
+			}
+			blockRange = max(blockRange/2, 1)
+			continue
+		}
+		events = append(events, page...)
+		start = end + 1
+	}
+	return events, nil
+}
+
+// filterEvents gets the events from the contract in a block range (to the latest block
+// when toBlock is 0).
+func (c *Web3Client) filterEvents(contract *Contract, eventName string, fromBlock, toBlock int64, query ...[]any) ([]ethtypes.Log, error) {
 	ctx := context.Background()
 	end := uint64(toBlock)
 
@@ -422,30 +493,63 @@ func (c *Web3Client) GetEvents(contract *Contract, eventName string, fromBlock,
 	}
 
 	// Get contract logs
-	logs, _, err := contract.FilterLogs(opts, eventName)
+	logs, sub, err := contract.FilterLogs(opts, eventName, query...)
 	if err != nil {
-		log.Fatalf("Failed to get events: %v", err)
+		return nil, err
 	}
 
-	// Convert chan to slice
+	// Convert chan to slice (the chan is never closed, the subscription ends instead)
 	var events []ethtypes.Log
-	for l := range logs {
-		events = append(events, l)
+	for {
+		select {
+		case l := <-logs:
+			events = append(events, l)
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			// Drain the logs delivered before the subscription ended
+			for {
+				select {
+				case l := <-logs:
+					events = append(events, l)
+				default:
+					return events, nil
+				}
+			}
+		}
 	}
-
-	return events
 }
 
-// EncodeFeedbackAuth encodes the feedback authorization data for a client.
+// EncodeFeedbackAuth ABI-encodes the feedback authorization data for a client.
 func (c *Web3Client) EncodeFeedbackAuth(
 	agentID big.Int,
-	clientAddress string,
+	clientAddress types.Address,
 	indexLimit big.Int,
 	expiry big.Int,
 	chainID big.Int,
-	identityRegistry string,
-	signerAddress string,
+	identityRegistry types.Address,
+	signerAddress types.Address,
 ) string {
+	// Pack arguments to get the encoded data
+	encoded, err := feedbackAuthArguments().Pack(
+		&agentID,
+		clientAddress.Common(),
+		&indexLimit,
+		&expiry,
+		&chainID,
+		identityRegistry.Common(),
+		signerAddress.Common(),
+	)
+	if err != nil {
+		log.Fatalf("Failed to pack data: %v", err)
+	}
+
+	return hexutil.Encode(encoded)
+}
+
+// feedbackAuthArguments returns the ABI arguments of the feedback authorization data.
+func feedbackAuthArguments() ethabi.Arguments {
 
 	// Helper function to create ABI type
 	mustABIType := func(typeString string) ethabi.Type {
@@ -457,7 +561,7 @@ func (c *Web3Client) EncodeFeedbackAuth(
 	}
 
 	// Create ABI arguments
-	arguments := ethabi.Arguments{
+	return ethabi.Arguments{
 		{
 			Type: mustABIType("uint256"),
 			Name: "agentId",
@@ -487,52 +591,77 @@ func (c *Web3Client) EncodeFeedbackAuth(
 			Name: "signerAddress",
 		},
 	}
+}
 
-	// Pack arguments to get the encoded data
-	encoded, err := arguments.Pack(agentID, clientAddress, indexLimit, expiry, chainID, identityRegistry, signerAddress)
+// SignMessage signs an EIP-191 personal message with the account signer (supported by all
+// signers, see SignText).
+func (c *Web3Client) SignMessage(message string) string {
+	return c.SignText([]byte(message))
+}
+
+// SignHash signs a 32-byte hash with the account signer (no message prefix is added).
+func (c *Web3Client) SignHash(hash []byte) string {
+	if c.Signer == nil {
+		log.Fatal("No signer available for signing")
+	}
+
+	signature, err := c.Signer.SignHash(hash)
 	if err != nil {
-		log.Fatalf("Failed to pack data: %v", err)
+		log.Fatalf("Failed to sign hash: %v", err)
 	}
 
-	// Convert bytes to hash and return hex string
-	return common.BytesToHash(encoded).Hex()
+	return hexutil.Encode(signature)
 }
 
-// SignMessage signs a message with the account's private key.
-func (c *Web3Client) SignMessage(message string) string {
-
-	// Check if private key is available
-	if c.privateKey != nil {
+// SignText signs an EIP-191 personal message with the account signer.
+func (c *Web3Client) SignText(message []byte) string {
+	if c.Signer == nil {
+		log.Fatal("No signer available for signing")
+	}
 
-		// Hash the message
-		hash := crypto.Keccak256Hash([]byte(message))
+	signature, err := SignText(c.Signer, message)
+	if err != nil {
+		log.Fatalf("Failed to sign message: %v", err)
+	}
 
-		// Sign the message
-		signature, err := crypto.Sign(hash.Bytes(), c.privateKey)
-		if err != nil {
-			log.Fatalf("Failed to sign message: %v", err)
-		}
+	return hexutil.Encode(signature)
+}
 
-		// Encode the signature
-		return hexutil.Encode(signature)
+// SignTypedData signs EIP-712 typed data with the account signer.
+func (c *Web3Client) SignTypedData(typedData apitypes.TypedData) string {
+	if c.Signer == nil {
+		log.Fatal("No signer available for signing")
 	}
 
-	// TODO: signer limitations
-	log.Fatal("signer limitations")
+	signature, err := c.Signer.SignTypedData(typedData)
+	if err != nil {
+		log.Fatalf("Failed to sign typed data: %v", err)
+	}
 
-	return ""
+	return hexutil.Encode(signature)
 }
 
-// RecoverAddress recovers the address from a message and signature.
+// RecoverAddress recovers the address from an EIP-191 personal message and signature
+// (see SignMessage).
 func (c *Web3Client) RecoverAddress(message, signature string) string {
-	hash := crypto.Keccak256Hash([]byte(message))
+	hash := accounts.TextHash([]byte(message))
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		log.Fatalf("Invalid signature: %s", signature)
+	}
 
-	sigPublicKey, err := crypto.Ecrecover(hash.Bytes(), []byte(signature))
+	// Normalize the recovery ID (27/28 to 0/1)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, sig)
 	if err != nil {
 		log.Fatalf("Failed to recover public key: %v", err)
 	}
 
-	return common.BytesToAddress(sigPublicKey).Hex()
+	return crypto.PubkeyToAddress(*publicKey).Hex()
 }
 
 // Keccak256 computes the Keccak-256 hash of the input data.
@@ -569,6 +698,15 @@ func (c *Web3Client) GetBalance(address string) *big.Int {
 	return balance
 }
 
+// GetBlockNumber gets the number of the most recent block.
+func (c *Web3Client) GetBlockNumber() int64 {
+	blockNumber, err := c.Provider.BlockNumber(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to get block number: %v", err)
+	}
+	return int64(blockNumber)
+}
+
 // GetTransactionCount gets the transaction count of the address.
 func (c *Web3Client) GetTransactionCount(address string) int64 {
 	nonce, err := c.Provider.PendingNonceAt(context.Background(), common.HexToAddress(address))
@@ -578,34 +716,239 @@ func (c *Web3Client) GetTransactionCount(address string) int64 {
 	return int64(nonce)
 }
 
-// Address gets the account address (if signer is available).
+// Address gets the account address (empty if no signer is available).
 func (c *Web3Client) Address() types.Address {
-	if c.privateKey != nil {
-		publicKey := c.privateKey.Public().(*ecdsa.PublicKey)
-		publicKeyBytes := crypto.FromECDSAPub(publicKey)
-		return common.BytesToAddress(publicKeyBytes).Hex()
+	if c.Signer == nil {
+		return ""
 	}
+	return types.AddressFromCommon(c.Signer.Address())
+}
 
-	// TODO: signer limitations
-	log.Fatal("signer limitations")
+// GetAddress gets the account address (fails if no signer is available).
+func (c *Web3Client) GetAddress() types.Address {
+	if c.Signer == nil {
+		log.Fatal("No signer available")
+	}
+	return types.AddressFromCommon(c.Signer.Address())
+}
 
-	return ""
+// NonceManager returns the nonce manager of the client.
+func (c *Web3Client) NonceManager() *NonceManager {
+	return c.nonces
 }
 
-// GetAddress gets the account address (if signer is available).
-func (c *Web3Client) GetAddress() types.Address {
-	if c.privateKey != nil {
-		publicKey := c.privateKey.Public().(*ecdsa.PublicKey)
-		publicKeyBytes := crypto.FromECDSAPub(publicKey)
-		return common.BytesToAddress(publicKeyBytes).Hex()
+// sendTransaction sends a transaction with the next nonce of the signer. The fee budget
+// is checked before sending, transactions rejected because of a nonce conflict are
+// retried after resyncing the nonce, and reverts (e.g. during gas estimation) are decoded.
+// The transaction is signed first, and signed (when set) is called before it is broadcast
+// (an error of signed aborts the transaction).
+// It is safe to call sendTransaction from multiple goroutines.
+func (c *Web3Client) sendTransaction(
+	contract *Contract,
+	options TransactionOptions,
+	data []byte,
+	signed func(txHash string) error,
+) (*ethtypes.Transaction, error) {
+	ctx := context.Background()
+
+	// Transactions signed by earlier attempts, which may have reached the node even though
+	// their broadcast failed
+	attempted := []*ethtypes.Transaction{}
+	for attempt := 1; ; attempt++ {
+		// Build transaction options (the gas limit is estimated when not set)
+		opts := c.newTransactOpts(ctx, options)
+
+		if err := c.checkTransactionBudget(ctx, contract, opts, data); err != nil {
+			c.nonces.Release(opts.From, opts.Nonce.Uint64())
+			return nil, err
+		}
+
+		opts.NoSend = true
+		tx, err := contract.RawTransact(opts, data)
+		if err == nil {
+			if signed != nil {
+				if err := signed(tx.Hash().Hex()); err != nil {
+					c.nonces.Release(opts.From, opts.Nonce.Uint64())
+					return nil, fmt.Errorf("transaction not sent: %w", err)
+				}
+			}
+			attempted = append(attempted, tx)
+			err = c.Provider.SendTransaction(ctx, tx)
+		}
+		if err == nil || isAlreadyKnown(err) {
+			// An already known transaction is this signed transaction, already in the pool
+			return tx, nil
+		}
+
+		if isNonceConflict(err) {
+			// The nonce may be used by an earlier attempt whose broadcast reached the node,
+			// resending would duplicate the write
+			if known := c.knownTransaction(ctx, attempted); known != nil {
+				return known, nil
+			}
+			if attempt < NONCE_CONFLICT_RETRIES {
+				c.nonces.Resync(opts.From)
+				continue
+			}
+		}
+
+		c.nonces.Release(opts.From, opts.Nonce.Uint64())
+		return nil, fmt.Errorf("failed to send transaction: %w", decodeTransactionError(err))
 	}
+}
 
-	// TODO: signer limitations
-	log.Fatal("signer limitations")
+// knownTransaction returns the first of the given transactions known to the node (pending
+// or mined), or nil.
+func (c *Web3Client) knownTransaction(ctx context.Context, txs []*ethtypes.Transaction) *ethtypes.Transaction {
+	for _, tx := range txs {
+		if _, _, err := c.Provider.TransactionByHash(ctx, tx.Hash()); err == nil {
+			return tx
+		}
+		if _, err := c.Provider.TransactionReceipt(ctx, tx.Hash()); err == nil {
+			return tx
+		}
+	}
+	return nil
+}
 
-	return ""
+// newTransactOpts builds the options of the next transaction of the signer.
+func (c *Web3Client) newTransactOpts(ctx context.Context, options TransactionOptions) *bind.TransactOpts {
+	from := c.Signer.Address()
+
+	nonce, err := c.nonces.Next(ctx, from)
+	if err != nil {
+		log.Fatalf("Failed to get pending nonce: %v", err)
+	}
+
+	opts := &bind.TransactOpts{
+		From:     from,
+		Nonce:    new(big.Int).SetUint64(nonce),
+		Signer:   c.signerFn(),
+		GasLimit: options.GasLimit.Uint64(),
+		Context:  ctx,
+	}
+
+	// Set either the legacy or the dynamic fee fields (never both)
+	fees := c.transactionFees(ctx, options)
+	if fees.IsDynamic() {
+		opts.GasFeeCap = fees.GasFeeCap
+		opts.GasTipCap = fees.GasTipCap
+	} else {
+		opts.GasPrice = fees.GasPrice
+	}
+
+	return opts
+}
+
+// transactionFees computes the fees of a transaction. Fees set in the transaction
+// options take precedence over the fee strategy.
+func (c *Web3Client) transactionFees(ctx context.Context, options TransactionOptions) TransactionFees {
+	var fees TransactionFees
+	var err error
+
+	if options.GasPrice.Sign() > 0 || options.MaxFeePerGas.Sign() > 0 {
+		fees, err = fixedFees(&options.GasPrice, &options.MaxFeePerGas, &options.MaxPriorityFeePerGas)
+	} else {
+		strategy := c.FeeStrategy
+		if strategy == nil {
+			strategy = DefaultFeeStrategy(c.ChainID)
+		}
+		fees, err = strategy.Fees(ctx, c.Provider)
+	}
+	if err != nil {
+		log.Fatalf("Failed to compute transaction fees: %v", err)
+	}
+
+	return fees
+}
+
+// checkTransactionBudget checks that the maximum fee of a transaction is within the budget
+// before it is sent. The gas limit is estimated when not set (and kept in the options).
+func (c *Web3Client) checkTransactionBudget(ctx context.Context, contract *Contract, opts *bind.TransactOpts, data []byte) error {
+	if c.MaxFeeBudget == nil {
+		return nil
+	}
+
+	if opts.GasLimit == 0 {
+		to := contract.Address()
+		gas, err := c.Provider.EstimateGas(ctx, ethereum.CallMsg{
+			From:      opts.From,
+			To:        &to,
+			Data:      data,
+			GasPrice:  opts.GasPrice,
+			GasFeeCap: opts.GasFeeCap,
+			GasTipCap: opts.GasTipCap,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to estimate gas: %w", decodeTransactionError(err))
+		}
+		opts.GasLimit = gas
+	}
+
+	// The fee cap is the gas price for legacy transactions
+	feeCap := opts.GasFeeCap
+	if feeCap == nil {
+		feeCap = opts.GasPrice
+	}
+	return c.checkMaxFee(new(big.Int).Mul(new(big.Int).SetUint64(opts.GasLimit), feeCap))
+}
+
+// checkFeeBudget checks that the maximum fee of a transaction is within the budget.
+func (c *Web3Client) checkFeeBudget(tx *ethtypes.Transaction) error {
+	if c.MaxFeeBudget == nil {
+		return nil
+	}
+
+	// The fee cap is the gas price for legacy transactions
+	return c.checkMaxFee(new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap()))
+}
+
+// checkMaxFee checks that a maximum transaction fee is within the budget.
+func (c *Web3Client) checkMaxFee(maxFee *big.Int) error {
+	if c.MaxFeeBudget != nil && maxFee.Cmp(c.MaxFeeBudget) > 0 {
+		return fmt.Errorf("%w: max fee %s wei exceeds budget %s wei", ErrFeeBudgetExceeded, maxFee, c.MaxFeeBudget)
+	}
+	return nil
+}
+
+// signerFn adapts the account signer to the signer function used by bound contracts.
+func (c *Web3Client) signerFn() bind.SignerFn {
+	chainID := c.ChainID.BigInt()
+	return func(address common.Address, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+		if address != c.Signer.Address() {
+			return nil, bind.ErrNotAuthorized
+		}
+		return c.Signer.SignTx(tx, chainID)
+	}
+}
+
+// failed records the failure of a simulation, decoding the revert reason.
+func (r SimulationResult) failed(err error) SimulationResult {
+	r.Err = decodeTransactionError(err)
+	errors.As(r.Err, &r.Revert)
+	return r
 }
 
 // ...
 
 type Contract = bind.BoundContract
+
+// SimulationResult is the result of a simulated (dry run) transaction.
+type SimulationResult struct {
+	From       types.Address
+	To         types.Address
+	Method     string
+	Calldata   string // hex encoded
+	ReturnData string // hex encoded
+	GasLimit   uint64
+	Fees       TransactionFees
+	MaxFee     *big.Int // gas limit times max fee per gas in wei
+
+	// WithinBudget is false when the max fee exceeds the max fee budget of the client.
+	WithinBudget bool
+
+	// Success is false when the transaction would fail (see Err and Revert).
+	Success bool
+	Err     error
+	Revert  *RevertError // decoded revert (nil when not reverted)
+}
@@ -11,6 +11,7 @@ require (
 	github.com/ipfs/kubo v0.39.0
 	github.com/multiformats/go-multiaddr v0.16.1
 	github.com/vektah/gqlparser/v2 v2.5.31
+	golang.org/x/text v0.31.0
 )
 
 require (
@@ -110,7 +111,6 @@ require (
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/text v0.31.0 // indirect
 	golang.org/x/tools v0.39.0 // indirect
 	gonum.org/v1/gonum v0.16.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ZERO_ADDRESS is the zero address.
+const ZERO_ADDRESS Address = "0x0000000000000000000000000000000000000000"
+
+// ErrInvalidAddress is returned (wrapped) for malformed addresses and addresses with an
+// invalid EIP-55 checksum.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Address is the address of an Ethereum account (0x...).
+//
+// Addresses are kept as given (the subgraph uses lowercase addresses, registration files
+// and users usually checksummed addresses) and compare case-insensitively with Equal. The
+// canonical forms are Lower (subgraph queries, map keys) and Checksum (display, JSON).
+type Address string
+
+// ParseAddress parses a hex address. Mixed case addresses must have a valid EIP-55
+// checksum (all lowercase and all uppercase addresses are not checksummed).
+func ParseAddress(value string) (Address, error) {
+	address := Address(strings.TrimSpace(value))
+	if err := address.Validate(); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// MustParseAddress parses a hex address and panics if it is invalid (constants).
+func MustParseAddress(value string) Address {
+	address, err := ParseAddress(value)
+	if err != nil {
+		panic(err)
+	}
+	return address
+}
+
+// Validate checks the address is a 20 bytes hex address with a valid EIP-55 checksum
+// (when mixed case).
+func (a Address) Validate() error {
+	if !strings.HasPrefix(string(a), "0x") || !common.IsHexAddress(string(a)) {
+		return fmt.Errorf("%w: %q", ErrInvalidAddress, string(a))
+	}
+
+	hex := string(a[2:])
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && string(a) != a.Checksum() {
+		return fmt.Errorf("%w: %q has an invalid EIP-55 checksum", ErrInvalidAddress, string(a))
+	}
+	return nil
+}
+
+// Checksum returns the EIP-55 checksummed form of the address.
+func (a Address) Checksum() string {
+	return common.HexToAddress(string(a)).Hex()
+}
+
+// Lower returns the lowercase form of the address.
+func (a Address) Lower() string {
+	return strings.ToLower(string(a))
+}
+
+// Common returns the go-ethereum address.
+func (a Address) Common() common.Address {
+	return common.HexToAddress(string(a))
+}
+
+// Equal checks if two addresses are the same account (case-insensitive).
+func (a Address) Equal(other Address) bool {
+	return strings.EqualFold(string(a), string(other))
+}
+
+// IsZero checks if the address is unset or the zero address.
+func (a Address) IsZero() bool {
+	return a == "" || a.Equal(ZERO_ADDRESS)
+}
+
+// String returns the checksummed form of valid addresses, or the address as given.
+func (a Address) String() string {
+	if !common.IsHexAddress(string(a)) {
+		return string(a)
+	}
+	return a.Checksum()
+}
+
+// MarshalText encodes the address in its checksummed form (JSON strings and map keys), so
+// that it decodes with UnmarshalText. Malformed addresses are an error (an empty address
+// is unset).
+func (a Address) MarshalText() ([]byte, error) {
+	if a == "" {
+		return []byte{}, nil
+	}
+	if !common.IsHexAddress(string(a)) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, string(a))
+	}
+	return []byte(a.Checksum()), nil
+}
+
+// UnmarshalText decodes and validates an address (an empty address is unset).
+func (a *Address) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = ""
+		return nil
+	}
+	address, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = address
+	return nil
+}
+
+// AddressFromCommon returns the checksummed address of a go-ethereum address.
+func AddressFromCommon(address common.Address) Address {
+	return Address(address.Hex())
+}
+
+// AddressList converts a list of trusted addresses (e.g. decoded data) to addresses.
+func AddressList(values []string) []Address {
+	addresses := make([]Address, len(values))
+	for idx, value := range values {
+		addresses[idx] = Address(value)
+	}
+	return addresses
+}
+
+// LowerAddresses returns the lowercase forms of addresses (subgraph filters).
+func LowerAddresses(addresses []Address) []string {
+	lower := make([]string, len(addresses))
+	for idx, address := range addresses {
+		lower[idx] = address.Lower()
+	}
+	return lower
+}
+
+// ContainsAddress checks if an address is in a list (case-insensitive).
+func ContainsAddress(addresses []Address, address Address) bool {
+	for _, item := range addresses {
+		if item.Equal(address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "checksummed", value: checksummed, valid: true},
+		{name: "lowercase", value: strings.ToLower(checksummed), valid: true},
+		{name: "uppercase", value: "0x" + strings.ToUpper(checksummed[2:]), valid: true},
+		{name: "zero", value: string(ZERO_ADDRESS), valid: true},
+		{name: "surrounding spaces", value: " " + checksummed + "\n", valid: true},
+		{name: "invalid checksum", value: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"},
+		{name: "missing prefix", value: checksummed[2:]},
+		{name: "uppercase prefix", value: "0X" + checksummed[2:]},
+		{name: "too short", value: checksummed[:41]},
+		{name: "too long", value: checksummed + "0"},
+		{name: "not hex", value: "0x" + strings.Repeat("g", 40)},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.value)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidAddress) {
+					t.Fatalf("ParseAddress(%q) error = %v, want ErrInvalidAddress", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAddress(%q) error: %v", tt.value, err)
+			}
+			if string(address) != strings.TrimSpace(tt.value) {
+				t.Errorf("ParseAddress(%q) = %q, want the address as given", tt.value, address)
+			}
+		})
+	}
+}
+
+func TestAddressForms(t *testing.T) {
+	address := Address(strings.ToLower(checksummed))
+
+	if address.Checksum() != checksummed {
+		t.Errorf("Checksum() = %q, want %q", address.Checksum(), checksummed)
+	}
+	if Address(checksummed).Lower() != string(address) {
+		t.Errorf("Lower() = %q, want %q", Address(checksummed).Lower(), address)
+	}
+	if address.String() != checksummed {
+		t.Errorf("String() = %q, want %q", address.String(), checksummed)
+	}
+	if Address("owner").String() != "owner" {
+		t.Errorf("String() of an invalid address = %q, want it as given", Address("owner").String())
+	}
+	if !address.Equal(checksummed) {
+		t.Errorf("Equal() = false for the same account in different cases")
+	}
+	if !Address("").IsZero() || !Address(strings.ToUpper(string(ZERO_ADDRESS))).IsZero() || address.IsZero() {
+		t.Errorf("IsZero() mismatch")
+	}
+	if !ContainsAddress([]Address{ZERO_ADDRESS, checksummed}, address) {
+		t.Errorf("ContainsAddress() = false, want true")
+	}
+}
+
+func TestAddressJSONRoundTrip(t *testing.T) {
+	type document struct {
+		Wallet  Address           `json:"wallet"`
+		Owners  []Address         `json:"owners"`
+		Balance map[Address]int64 `json:"balance"`
+	}
+
+	tests := []struct {
+		name    string
+		address Address
+		want    Address
+	}{
+		{name: "checksummed", address: checksummed, want: checksummed},
+		{name: "lowercase", address: Address(strings.ToLower(checksummed)), want: checksummed},
+		{name: "invalid checksum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", want: checksummed},
+		{name: "unset", address: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(document{
+				Wallet:  tt.address,
+				Owners:  []Address{tt.address},
+				Balance: map[Address]int64{tt.address: 1},
+			})
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+
+			decoded := document{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s) error: %v", data, err)
+			}
+			if decoded.Wallet != tt.want || decoded.Owners[0] != tt.want || decoded.Balance[tt.want] != 1 {
+				t.Errorf("round trip of %q = %+v, want %q", tt.address, decoded, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressJSONInvalid(t *testing.T) {
+	for _, address := range []Address{"0x1234", "owner", Address(checksummed + "0")} {
+		if _, err := json.Marshal(address); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("Marshal(%q) error = %v, want ErrInvalidAddress", address, err)
+		}
+	}
+
+	for _, data := range []string{`"0x1234"`, `"` + checksummed[2:] + `"`, `"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"`} {
+		var address Address
+		if err := json.Unmarshal([]byte(data), &address); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidAddress", data, err)
+		}
+	}
+}
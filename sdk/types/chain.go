@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ryanchristo/agent0-go/sdk/caip"
+)
+
+// ErrInvalidChainID is returned (wrapped) for malformed and non-positive chain IDs.
+var ErrInvalidChainID = errors.New("invalid chain ID")
+
+// ParseChainID parses a decimal EVM chain ID or a CAIP-2 EVM chain ID (eip155:84532).
+func ParseChainID(value string) (ChainID, error) {
+	value = strings.TrimSpace(value)
+
+	var chainID int64
+	if strings.Contains(value, ":") {
+		caipChainID, err := caip.ParseChainID(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		}
+		chainID, err = caipChainID.EVMChainID()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		}
+	} else {
+		var err error
+		chainID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidChainID, value)
+		}
+	}
+
+	if err := ChainID(chainID).Validate(); err != nil {
+		return 0, err
+	}
+	return ChainID(chainID), nil
+}
+
+// Validate checks the chain ID is positive.
+func (c ChainID) Validate() error {
+	if c <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidChainID, c)
+	}
+	return nil
+}
+
+// BigInt returns the chain ID as a big integer (transactions and signatures).
+func (c ChainID) BigInt() *big.Int {
+	return big.NewInt(int64(c))
+}
+
+// CAIP2 returns the CAIP-2 chain ID (eip155:chainID).
+func (c ChainID) CAIP2() string {
+	return caip.EIP155ChainID(int64(c)).String()
+}
+
+// String returns the decimal chain ID.
+func (c ChainID) String() string {
+	return strconv.FormatInt(int64(c), 10)
+}
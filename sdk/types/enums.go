@@ -36,3 +36,24 @@ const (
 	// TRUST_MODEL_TEE_ATTESTATION is the tee attestation trust model.
 	TRUST_MODEL_TEE_ATTESTATION TrustModel = "tee-attestation"
 )
+
+// DedupKey is a custom type for enumeration.
+type DedupKey string
+
+const (
+	// DEDUP_KEY_REGISTRATIONS is the dedup key for the ERC-8004 registrations of the registration file.
+	DEDUP_KEY_REGISTRATIONS DedupKey = "registrations"
+
+	// DEDUP_KEY_WALLET is the dedup key for the agent wallet address.
+	DEDUP_KEY_WALLET DedupKey = "wallet"
+
+	// DEDUP_KEY_ENS is the dedup key for the ENS name.
+	DEDUP_KEY_ENS DedupKey = "ens"
+
+	// DEDUP_KEY_DID is the dedup key for the DID.
+	DEDUP_KEY_DID DedupKey = "did"
+
+	// DEDUP_KEY_NAME_DESCRIPTION is the fallback dedup key for the name and description.
+	// It only applies to agents without any of the other configured identity keys.
+	DEDUP_KEY_NAME_DESCRIPTION DedupKey = "nameDescription"
+)
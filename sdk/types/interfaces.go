@@ -56,6 +56,9 @@ type RegistrationFile struct {
 	// Metadata is the metadata of the agent.
 	Metadata map[string]any `json:"metadata"`
 
+	// Registrations is the ERC-8004 registrations of the agent (eip155:chainID:registry:agentID).
+	Registrations []string `json:"registrations,omitempty"`
+
 	// UpdatedAt is the timestamp of the last update.
 	UpdatedAt Timestamp `json:"updatedAt"`
 }
@@ -113,6 +116,12 @@ type AgentSummary struct {
 	// MCPResources is the MCP resources of the agent.
 	MCPResources []string `json:"mcpResources"`
 
+	// OASFSkills is the OASF skills of the agent.
+	OASFSkills []string `json:"oasfSkills"`
+
+	// OASFDomains is the OASF domains of the agent.
+	OASFDomains []string `json:"oasfDomains"`
+
 	// Active is the active status of the agent.
 	Active bool `json:"active"`
 
@@ -121,6 +130,54 @@ type AgentSummary struct {
 
 	// Extras is the extras of the agent.
 	Extras map[string]any `json:"extras"`
+
+	// Registrations is the ERC-8004 registrations of the agent (eip155:chainID:registry:agentID).
+	Registrations []string `json:"registrations"`
+
+	// AgentURI is the URI of the registration file of the agent.
+	AgentURI URI `json:"agentUri,omitempty"`
+
+	// Deployments is the deployments of a deduplicated agent across chains (only set when deduplicated).
+	Deployments []AgentDeployment `json:"deployments,omitempty"`
+
+	// Reputation is the reputation of the agent (only set when requested).
+	Reputation *AgentReputation `json:"reputation,omitempty"`
+}
+
+// AgentDeployment is a deployment of an agent on a chain.
+type AgentDeployment struct {
+	// ChainID is the chain ID of the deployment.
+	ChainID ChainID `json:"chainId"`
+
+	// AgentID is the ID of the agent on the chain.
+	AgentID AgentID `json:"agentId"`
+}
+
+// AgentReputation is the reputation information of an agent.
+type AgentReputation struct {
+	// FeedbackCount is the number of feedback entries of the agent.
+	FeedbackCount int64 `json:"feedbackCount"`
+
+	// AverageScore is the average feedback score of the agent (0-100).
+	AverageScore float64 `json:"averageScore"`
+
+	// ScoreDistribution is the feedback count per score bucket (0-20, 21-40, 41-60, 61-80, 81-100).
+	ScoreDistribution []int64 `json:"scoreDistribution"`
+
+	// Validations is the validation summary of the agent.
+	Validations ValidationSummary `json:"validations"`
+}
+
+// ValidationSummary is the summary of the validations of an agent.
+type ValidationSummary struct {
+	// Total is the total number of validations.
+	Total int64 `json:"total"`
+
+	// Completed is the number of completed validations.
+	Completed int64 `json:"completed"`
+
+	// AverageScore is the average response of the completed validations (0-100).
+	AverageScore float64 `json:"averageScore"`
 }
 
 // Feedback is the feedback associated with an agent.
@@ -238,11 +295,46 @@ type SearchParams struct {
 	// MCPResources is the MCP resources of the agent to search.
 	MCPResources []string `json:"mcpResources,omitempty"`
 
+	// OASFSkills is the OASF skills of the agent to search (hierarchical, e.g. a category matches its skills).
+	OASFSkills []string `json:"oasfSkills,omitempty"`
+
+	// OASFDomains is the OASF domains of the agent to search (hierarchical, e.g. a category matches its domains).
+	OASFDomains []string `json:"oasfDomains,omitempty"`
+
 	// Active is the active status of the agent to search.
 	Active *bool `json:"active,omitempty"`
 
 	// X402Support is the X402 support status of the agent to search.
 	X402Support *bool `json:"x402Support,omitempty"`
+
+	// DeduplicateBy is the identity keys used to merge the same agent across chains (empty disables).
+	DeduplicateBy []DedupKey `json:"deduplicateBy,omitempty"`
+
+	// Reputation search criteria (reputation filters imply IncludeReputation)
+
+	// IncludeReputation is the include reputation status (attaches reputation to each result).
+	IncludeReputation bool `json:"includeReputation,omitempty"`
+
+	// MinFeedbackCount is the minimum feedback count of the agent to search (nil when unset).
+	MinFeedbackCount *int64 `json:"minFeedbackCount,omitempty"`
+
+	// MinAverageScore is the minimum average score of the agent to search (0-100, nil when unset).
+	MinAverageScore *float64 `json:"minAverageScore,omitempty"`
+
+	// MaxAverageScore is the maximum average score of the agent to search (0-100, nil when unset).
+	MaxAverageScore *float64 `json:"maxAverageScore,omitempty"`
+
+	// MinCompletedValidations is the minimum completed validations of the agent to search (nil when unset).
+	MinCompletedValidations *int64 `json:"minCompletedValidations,omitempty"`
+
+	// MinAverageValidationScore is the minimum average validation score of the agent to search (0-100, nil when unset).
+	MinAverageValidationScore *float64 `json:"minAverageValidationScore,omitempty"`
+}
+
+// HasReputationFilters checks if any reputation search criteria is set.
+func (p SearchParams) HasReputationFilters() bool {
+	return p.MinFeedbackCount != nil || p.MinAverageScore != nil || p.MaxAverageScore != nil ||
+		p.MinCompletedValidations != nil || p.MinAverageValidationScore != nil
 }
 
 // SearchFeedbackParams is the search criteria for searching feedback.
@@ -268,11 +360,11 @@ type SearchFeedbackParams struct {
 	// Names is the names of the MCP tools/resources/prompts to search.
 	Names []string `json:"names,omitempty"`
 
-	// MinScore is the minimum score to search (0-100).
-	MinScore int64 `json:"minScore,omitempty"`
+	// MinScore is the minimum score to search (0-100, nil when unset).
+	MinScore *int64 `json:"minScore,omitempty"`
 
-	// MaxScore is the maximum score to search (0-100).
-	MaxScore int64 `json:"maxScore,omitempty"`
+	// MaxScore is the maximum score to search (0-100, nil when unset).
+	MaxScore *int64 `json:"maxScore,omitempty"`
 
 	// IncludeRevoked is the include revoked status to search.
 	IncludeRevoked bool `json:"includeRevoked,omitempty"`
@@ -289,6 +381,9 @@ type SearchResultMeta struct {
 	// FailedChains is the chains that failed.
 	FailedChains []ChainID `json:"failedChains"`
 
+	// TruncatedChains is the chains with more results than SEARCH_MAX_RESULTS_PER_CHAIN (results are incomplete).
+	TruncatedChains []ChainID `json:"truncatedChains"`
+
 	// TotalResults is the total number of results.
 	TotalResults int64 `json:"totalResults"`
 
@@ -3,11 +3,8 @@ package types
 // AgentID is the ID of the agent (chainID:tokenID or tokenID).
 type AgentID = string
 
-// Chain ID is the chain ID.
-type ChainID = int64
-
-// Ethereum address is the address of the Ethereum account (0x...).
-type Address = string
+// ChainID is the EVM chain ID (see ParseChainID).
+type ChainID int64
 
 // URI is the URI of the resource (https://... or ipfs://...).
 type URI = string
@@ -9,14 +9,32 @@ var IPFS_GATEWAYS = []string{
 
 // TIMEOUTS is a map of timeout values in milliseconds.
 var TIMEOUTS = map[string]int64{
-	"IPFS_GATEWAY":             10000, // 10 seconds
-	"PINATA_UPLOAD":            80000, // 80 seconds
-	"TRANSACTION_WAIT":         30000, // 30 seconds
-	"ENDPOINT_CRAWLER_DEFAULT": 5000,  // 5 seconds
+	"IPFS_GATEWAY":              10000,  // 10 seconds
+	"PINATA_UPLOAD":             80000,  // 80 seconds
+	"TRANSACTION_WAIT":          30000,  // 30 seconds
+	"ENDPOINT_CRAWLER_DEFAULT":  5000,   // 5 seconds
+	"SEARCH_ACROSS_CHAINS":      30000,  // 30 seconds
+	"SAVED_SEARCH_POLL":         15000,  // 15 seconds
+	"SAVED_SEARCH_REFRESH":      300000, // 5 minutes
+	"EXTERNAL_SIGNER":           120000, // 2 minutes (allows manual approval)
+	"TRANSACTION_POLL":          1000,   // 1 second
+	"TRANSACTION_REPLACE_AFTER": 60000,  // 1 minute
+	"RPC_RETRY_BACKOFF":         250,    // 250 milliseconds
+	"RPC_RETRY_MAX_BACKOFF":     5000,   // 5 seconds
+	"RPC_CIRCUIT_COOLDOWN":      30000,  // 30 seconds
 }
 
 // DEFAULTS is a map of default values.
 var DEFAULTS = map[string]int64{
-	"FEEDBACK_EXPIRY_HOURS": 24,
-	"SEARCH_PAGE_SIZE":      50,
+	"FEEDBACK_EXPIRY_HOURS":         24,
+	"SEARCH_PAGE_SIZE":              50,
+	"SEARCH_MAX_RESULTS_PER_CHAIN":  5000,
+	"TRANSACTION_FEE_BUMP_PERCENT":  15,
+	"TRANSACTION_MAX_REPLACEMENTS":  3,
+	"MULTICALL_BATCH_SIZE":          200,
+	"RPC_BATCH_SIZE":                50,
+	"RPC_MAX_RETRIES":               4,
+	"RPC_CIRCUIT_FAILURE_THRESHOLD": 3,
+	"LOG_BLOCK_RANGE":               10000,
+	"REGISTRATION_FILE_READS":       8,
 }
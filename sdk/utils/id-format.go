@@ -2,45 +2,51 @@ package utils
 
 import (
 	"log"
+	"math/big"
 	"strconv"
 	"strings"
 
+	"github.com/ryanchristo/agent0-go/sdk/caip"
 	"github.com/ryanchristo/agent0-go/sdk/types"
 )
 
 // ParsedAgentID contains the parsed components of an agent ID.
 type ParsedAgentID struct {
-	ChainID types.ChainID
-	TokenID int64
+	ChainID  types.ChainID
+	Registry types.Address // identity registry ("" unless the agent ID is a global agent ID)
+	TokenID  *big.Int      // ERC-721 token ID (uint256)
 }
 
 // ParseAgentID parses an agent ID string and returns the components.
-// The agent ID string must be in the format "chainID:tokenID".
+// The agent ID string must be in the format "chainID:tokenID" or be an ERC-8004 global
+// agent ID "eip155:chainID:registry:tokenID" (see caip.ParseAgentID).
 func ParseAgentID(id types.AgentID) ParsedAgentID {
-	parts := strings.Split(id, ":")
-	if len(parts) != 2 {
-		log.Fatalf("Invalid agent ID format: %s. Expected format: chainID:tokenID", id)
-	}
-
-	chainID, err := strconv.ParseInt(parts[0], 10, 64)
+	agentID, err := caip.ParseAgentID(id)
 	if err != nil {
-		log.Fatalf("Invalid chain ID in agent ID %s: %v", id, err)
+		log.Fatalf("Invalid agent ID: %v", err)
 	}
 
-	tokenID, err := strconv.ParseInt(parts[1], 10, 64)
+	chainID, err := agentID.Chain.EVMChainID()
 	if err != nil {
-		log.Fatalf("Invalid token ID in agent ID %s: %v", id, err)
+		log.Fatalf("Invalid agent ID %s: %v", id, err)
 	}
 
 	return ParsedAgentID{
-		ChainID: chainID,
-		TokenID: tokenID,
+		ChainID:  types.ChainID(chainID),
+		Registry: types.Address(agentID.Registry),
+		TokenID:  agentID.TokenID,
 	}
 }
 
+// NormalizeAgentID returns the "chainID:tokenID" format of an agent ID.
+func NormalizeAgentID(id types.AgentID) types.AgentID {
+	parsed := ParseAgentID(id)
+	return FormattedAgentID(parsed.ChainID, parsed.TokenID.String())
+}
+
 // FormattedAgentID formats agent ID components into the format "chainID:tokenID".
 func FormattedAgentID(chainID types.ChainID, tokenID string) types.AgentID {
-	return types.AgentID(strconv.FormatInt(chainID, 10) + ":" + tokenID)
+	return types.AgentID(chainID.String() + ":" + tokenID)
 }
 
 // ParsedFeedbackID contains the parsed components of a feedback ID.
@@ -69,7 +75,7 @@ func ParseFeedbackID(id string) ParsedFeedbackID {
 	}
 
 	// Normalize address to lowercase for consistency
-	clientAddress = strings.ToLower(clientAddress)
+	clientAddress = types.Address(clientAddress.Lower())
 
 	return ParsedFeedbackID{
 		AgentID:       agentID,
@@ -81,7 +87,5 @@ func ParseFeedbackID(id string) ParsedFeedbackID {
 // FormattedFeedbackID formats feedback ID components into the format "agentID:clientAddress:feedbackIndex".
 func FormattedFeedbackID(agentID types.AgentID, clientAddress types.Address, feedbackIndex int64) types.FeedbackID {
 	// Normalize address to lowercase for consistency
-	clientAddress = strings.ToLower(clientAddress)
-
-	return types.FeedbackID(agentID + ":" + clientAddress + ":" + strconv.FormatInt(feedbackIndex, 10))
+	return types.FeedbackID(agentID + ":" + clientAddress.Lower() + ":" + strconv.FormatInt(feedbackIndex, 10))
 }
@@ -0,0 +1,8 @@
+package wordlists
+
+import _ "embed"
+
+// English is the BIP-39 English wordlist (2048 words, one per line).
+//
+//go:embed english.txt
+var English string